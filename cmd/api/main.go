@@ -14,14 +14,21 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/application"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/alert"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/audit"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/client"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/equipment"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/family"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/project"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/report"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/search"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/user"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/infra/http/handler"
 	custommiddleware "github.com/AugustoGuapo/concretrack-backoffice-be/internal/infra/http/middleware"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/infra/mail"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/infra/storage"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/infra/webhook"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
@@ -44,30 +51,64 @@ func main() {
 	}
 	defer db.Close()
 
+	if err := storage.Migrate(db, filepath.Join(root, "resources", "migrations")); err != nil {
+		log.Fatalf("error al migrar la base de datos: %v", err)
+	}
+
 	// --- Inyección de dependencias ---
+	auditRepo := storage.NewAuditRepository(db)
+	auditService := audit.NewAuditService(auditRepo)
+	auditHandler := handler.NewAuditHandler(auditService)
+
 	userRepo := storage.NewUserRepository(db)
-	userService := user.NewService(userRepo)
+	userService := user.NewService(userRepo, auditService)
 	authHandler := handler.NewAuthHandler(userService)
+	userHandler := handler.NewUserHandler(userService)
 
 	projectRepo := storage.NewProjectRepository(db)
-	projectService := project.NewService(projectRepo)
+	projectService := project.NewService(projectRepo, auditService)
 	projectHandler := handler.NewProjectHandler(projectService)
 
-	reportsService := application.NewReportsService(projectRepo)
-	reportsHandler := handler.NewReportsHandler(*reportsService)
-
 	clientRepo := storage.NewClientRepository(db)
-	clientService := client.NewClientService(clientRepo)
+	clientService := client.NewClientService(clientRepo, auditService)
 	clientHandler := handler.NewClientHandler(clientService)
 
 	familyRepo := storage.NewFamilyRepository(db)
-	familyService := family.NewFamilyService(familyRepo)
+	familyService := family.NewFamilyService(familyRepo, auditService)
 	familyHandler := handler.NewFamilyHandler(familyService)
 
+	// Sin SMTP_HOST no se envían reportes ni alertas por correo.
+	var mailSender report.Sender
+	if config, ok := mail.ConfigFromEnv(); ok {
+		mailSender = mail.NewSMTPSender(config)
+	}
+
+	alertRepo := storage.NewAlertRepository(db)
+	alertService := alert.NewAlertService(alertRepo, familyService, clientService, mailSender, webhook.NewPoster())
+	alertHandler := handler.NewAlertHandler(alertService)
+
 	memberRepo := storage.NewMemberRepository(db)
-	memberService := member.NewMemberService(memberRepo)
+	memberService := member.NewMemberService(memberRepo, auditService, alertService)
 	memberHandler := handler.NewMemberHandler(memberService)
 
+	reportRepo := storage.NewReportRepository(db)
+	reportsService := application.NewReportsService(projectRepo, familyService, clientService, memberService, reportRepo, mailSender)
+	reportsHandler := handler.NewReportsHandler(*reportsService)
+
+	labelsService := application.NewLabelsService(projectRepo, familyService)
+	labelsHandler := handler.NewLabelsHandler(labelsService)
+
+	controlChartsService := application.NewControlChartsService(projectService)
+	controlChartsHandler := handler.NewControlChartsHandler(controlChartsService)
+
+	equipmentRepo := storage.NewEquipmentRepository(db)
+	equipmentService := equipment.NewEquipmentService(equipmentRepo, auditService)
+	equipmentHandler := handler.NewEquipmentHandler(equipmentService)
+
+	searchRepo := storage.NewSearchRepository(db)
+	searchService := search.NewSearchService(searchRepo)
+	searchHandler := handler.NewSearchHandler(searchService)
+
 	// --- Router Chi ---
 	r := chi.NewRouter()
 
@@ -75,6 +116,7 @@ func main() {
 	r.Use(middleware.Logger)     // log bonito
 	r.Use(middleware.Recoverer)  // recupera de panics
 	r.Use(custommiddleware.CORS) // tu middleware de CORS
+	r.Use(custommiddleware.Authenticate(userService))
 
 	// --- Rutas ---
 	r.Post("/login", func(w http.ResponseWriter, r *http.Request) {
@@ -87,44 +129,80 @@ func main() {
 		json.NewEncoder(w).Encode(response)
 	})
 
+	r.Get("/audit", func(w http.ResponseWriter, r *http.Request) {
+		auditHandler.GetEntries(w, r)
+	})
+
+	r.Get("/search", func(w http.ResponseWriter, r *http.Request) {
+		searchHandler.Search(w, r)
+	})
+
 	// Grupo de proyectos
 	r.Route("/projects", func(r chi.Router) {
+		r.Use(custommiddleware.RequireAuth)
 
 		r.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
 			ID := chi.URLParam(r, "id")
 			numericID, err := strconv.Atoi(ID)
 			if err != nil {
 				http.Error(w, "project id must be numeric", http.StatusBadRequest)
+				return
 			}
 			projectHandler.GetProjectByID(w, r, numericID)
 		})
 
 		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
-			pageStr := r.URL.Query().Get("page")
-			if pageStr == "" {
-				// Por defecto puedes poner 1 o lanzar error
-				pageStr = "1"
-			}
+			projectHandler.GetProjects(w, r)
+		})
 
-			page, err := strconv.Atoi(pageStr)
-			if err != nil || page < 1 {
-				http.Error(w, "invalid page", http.StatusBadRequest)
-				return
-			}
+		r.Get("/{ID}/families", func(w http.ResponseWriter, r *http.Request) {
+			familyHandler.GetProjectFamilies(w, r)
+		})
 
-			projectHandler.GetProjects(w, r, page)
+		r.Get("/{ID}/families/{familyID}", func(w http.ResponseWriter, r *http.Request) {
+			familyHandler.GetProjectFamily(w, r)
 		})
 
 		r.Get("/{ID}/families/{familyID}/report", func(w http.ResponseWriter, r *http.Request) {
 			reportsHandler.GenerateReportForOneFamily(w, r)
 		})
 
+		r.Get("/{ID}/families/{familyID}/reports", func(w http.ResponseWriter, r *http.Request) {
+			reportsHandler.GetIssuedReports(w, r)
+		})
+
+		r.Post("/{ID}/families/{familyID}/reports", func(w http.ResponseWriter, r *http.Request) {
+			reportsHandler.IssueReport(w, r)
+		})
+
+		r.Get("/{ID}/families/{familyID}/reports/{reportID}/file", func(w http.ResponseWriter, r *http.Request) {
+			reportsHandler.DownloadIssuedReport(w, r)
+		})
+
+		r.Post("/{ID}/families/{familyID}/reports/{reportID}/send", func(w http.ResponseWriter, r *http.Request) {
+			reportsHandler.SendIssuedReport(w, r)
+		})
+
+		r.Get("/{ID}/families/{familyID}/labels", func(w http.ResponseWriter, r *http.Request) {
+			labelsHandler.GenerateFamilyLabels(w, r)
+		})
+
+		r.Get("/{ID}/fracture-types", func(w http.ResponseWriter, r *http.Request) {
+			projectHandler.GetFractureTypeStats(w, r)
+		})
+
 		r.Post("/", func(w http.ResponseWriter, r *http.Request) {
 			projectHandler.SaveProject(w, r)
 		})
+
+		r.Put("/{id}", func(w http.ResponseWriter, r *http.Request) {
+			projectHandler.UpdateProject(w, r)
+		})
 	})
 
 	r.Route("/clients", func(r chi.Router) {
+		r.Use(custommiddleware.RequireAuth)
+
 		r.Get("/{ID}", func(w http.ResponseWriter, r *http.Request) {
 			clientHandler.GetClient(w, r)
 		})
@@ -140,18 +218,220 @@ func main() {
 		r.Get("/{clientID}/projects", func(w http.ResponseWriter, r *http.Request) {
 			projectHandler.GetProjectsByClientID(w, r)
 		})
+
+		r.Put("/{ID}", func(w http.ResponseWriter, r *http.Request) {
+			clientHandler.UpdateClient(w, r)
+		})
+
+		r.Get("/{ID}/contacts", func(w http.ResponseWriter, r *http.Request) {
+			clientHandler.GetContacts(w, r)
+		})
+
+		r.Post("/{ID}/contacts", func(w http.ResponseWriter, r *http.Request) {
+			clientHandler.SaveContact(w, r)
+		})
+
+		r.Get("/{ID}/contacts/{contactID}", func(w http.ResponseWriter, r *http.Request) {
+			clientHandler.GetContact(w, r)
+		})
+
+		r.Put("/{ID}/contacts/{contactID}", func(w http.ResponseWriter, r *http.Request) {
+			clientHandler.UpdateContact(w, r)
+		})
+
+		r.Delete("/{ID}/contacts/{contactID}", func(w http.ResponseWriter, r *http.Request) {
+			clientHandler.DeleteContact(w, r)
+		})
 	})
 
 	r.Route("/families", func(r chi.Router) {
+		r.Use(custommiddleware.RequireAuth)
+
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			familyHandler.GetFamilies(w, r)
+		})
+
+		r.Get("/{ID}", func(w http.ResponseWriter, r *http.Request) {
+			familyHandler.GetFamily(w, r)
+		})
+
 		r.Post("/", func(w http.ResponseWriter, r *http.Request) {
 			familyHandler.SaveFamily(w, r)
 		})
 	})
 
+	r.Route("/equipment", func(r chi.Router) {
+		r.Use(custommiddleware.RequireAuth)
+
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			equipmentHandler.GetEquipmentList(w, r)
+		})
+
+		r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+			equipmentHandler.SaveEquipment(w, r)
+		})
+
+		r.Get("/{ID}", func(w http.ResponseWriter, r *http.Request) {
+			equipmentHandler.GetEquipment(w, r)
+		})
+
+		r.Put("/{ID}", func(w http.ResponseWriter, r *http.Request) {
+			equipmentHandler.UpdateEquipment(w, r)
+		})
+
+		r.Get("/{ID}/calibrations", func(w http.ResponseWriter, r *http.Request) {
+			equipmentHandler.GetCalibrations(w, r)
+		})
+
+		r.Post("/{ID}/calibrations", func(w http.ResponseWriter, r *http.Request) {
+			equipmentHandler.SaveCalibration(w, r)
+		})
+
+		r.Get("/{ID}/members", func(w http.ResponseWriter, r *http.Request) {
+			equipmentHandler.GetTestedMembers(w, r)
+		})
+	})
+
+	r.Route("/analytics", func(r chi.Router) {
+		r.Get("/quality", func(w http.ResponseWriter, r *http.Request) {
+			projectHandler.GetQualityStats(w, r)
+		})
+
+		r.Get("/control-charts", func(w http.ResponseWriter, r *http.Request) {
+			controlChartsHandler.GetControlCharts(w, r)
+		})
+
+		r.Get("/workload", func(w http.ResponseWriter, r *http.Request) {
+			memberHandler.GetWorkload(w, r)
+		})
+
+		r.Get("/backlog", func(w http.ResponseWriter, r *http.Request) {
+			memberHandler.GetBacklog(w, r)
+		})
+	})
+
+	r.Get("/fracture-types", func(w http.ResponseWriter, r *http.Request) {
+		memberHandler.GetFractureTypes(w, r)
+	})
+
 	r.Route("/members", func(r chi.Router) {
+		r.Use(custommiddleware.RequireAuth)
+
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			memberHandler.GetMembers(w, r)
+		})
+
 		r.Post("/", func(w http.ResponseWriter, r *http.Request) {
 			memberHandler.SaveMembers(w, r)
 		})
+
+		r.Post("/results/import", func(w http.ResponseWriter, r *http.Request) {
+			memberHandler.IngestMachineExport(w, r)
+		})
+
+		r.Get("/{ID}/corrections", func(w http.ResponseWriter, r *http.Request) {
+			memberHandler.GetMemberCorrections(w, r)
+		})
+
+		r.Post("/{ID}/corrections", func(w http.ResponseWriter, r *http.Request) {
+			memberHandler.RequestCorrection(w, r)
+		})
+
+		r.Get("/{ID}/custody", func(w http.ResponseWriter, r *http.Request) {
+			memberHandler.GetCustodyEvents(w, r)
+		})
+
+		r.Post("/{ID}/custody", func(w http.ResponseWriter, r *http.Request) {
+			memberHandler.RecordCustodyEvent(w, r)
+		})
+
+		r.Get("/{ID}/location", func(w http.ResponseWriter, r *http.Request) {
+			memberHandler.GetCurrentCustody(w, r)
+		})
+
+		r.Get("/{ID}/curve", func(w http.ResponseWriter, r *http.Request) {
+			memberHandler.GetCurve(w, r)
+		})
+
+		r.Put("/{ID}/curve", func(w http.ResponseWriter, r *http.Request) {
+			memberHandler.SaveCurve(w, r)
+		})
+	})
+
+	r.Get("/labels/lookup", func(w http.ResponseWriter, r *http.Request) {
+		memberHandler.LookupLabel(w, r)
+	})
+
+	r.Get("/custody", func(w http.ResponseWriter, r *http.Request) {
+		memberHandler.GetSpecimensAt(w, r)
+	})
+
+	r.Route("/alerts", func(r chi.Router) {
+		r.Use(custommiddleware.RequireAuth)
+
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			alertHandler.GetAlerts(w, r)
+		})
+
+		r.Get("/rules", func(w http.ResponseWriter, r *http.Request) {
+			alertHandler.GetRules(w, r)
+		})
+
+		r.Post("/rules", func(w http.ResponseWriter, r *http.Request) {
+			alertHandler.SaveRule(w, r)
+		})
+
+		r.Get("/rules/{ID}", func(w http.ResponseWriter, r *http.Request) {
+			alertHandler.GetRule(w, r)
+		})
+
+		r.Put("/rules/{ID}", func(w http.ResponseWriter, r *http.Request) {
+			alertHandler.UpdateRule(w, r)
+		})
+
+		r.Get("/{ID}", func(w http.ResponseWriter, r *http.Request) {
+			alertHandler.GetAlert(w, r)
+		})
+
+		r.Post("/{ID}/acknowledge", func(w http.ResponseWriter, r *http.Request) {
+			alertHandler.AcknowledgeAlert(w, r)
+		})
+
+		r.Post("/{ID}/resolve", func(w http.ResponseWriter, r *http.Request) {
+			alertHandler.ResolveAlert(w, r)
+		})
+	})
+
+	r.Route("/corrections", func(r chi.Router) {
+		r.Use(custommiddleware.RequireAuth)
+
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			memberHandler.GetCorrections(w, r)
+		})
+
+		r.Post("/{ID}/approve", func(w http.ResponseWriter, r *http.Request) {
+			memberHandler.ApproveCorrection(w, r)
+		})
+
+		r.Post("/{ID}/reject", func(w http.ResponseWriter, r *http.Request) {
+			memberHandler.RejectCorrection(w, r)
+		})
+	})
+
+	r.Route("/users", func(r chi.Router) {
+		r.Use(custommiddleware.RequireAuth)
+
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			userHandler.GetUsers(w, r)
+		})
+
+		r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+			userHandler.CreateUser(w, r)
+		})
+
+		r.Put("/{ID}", func(w http.ResponseWriter, r *http.Request) {
+			userHandler.UpdateUser(w, r)
+		})
 	})
 
 	// --- Inicio del servidor ---
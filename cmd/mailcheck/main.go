@@ -0,0 +1,53 @@
+// Command mailcheck sends a sample message through the SMTP server set in
+// .env, to check the mail setup against a local fake server such as MailHog.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"path/filepath"
+	"runtime"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/report"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/infra/mail"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	to := flag.String("to", "", "destinatario del correo de prueba")
+	flag.Parse()
+	if *to == "" {
+		log.Fatal("falta -to")
+	}
+
+	_, file, _, _ := runtime.Caller(0)
+	root := filepath.Join(filepath.Dir(file), "..", "..") // subir desde cmd/mailcheck
+	if err := godotenv.Load(filepath.Join(root, ".env")); err != nil {
+		log.Fatalf("Error loading .env file %v", err)
+	}
+
+	config, ok := mail.ConfigFromEnv()
+	if !ok {
+		log.Fatal("SMTP_HOST no está configurado")
+	}
+
+	// El adjunto ocupa varias líneas en base64 y el cuerpo lleva acentos, para
+	// revisar ambas codificaciones en el cliente.
+	content := make([]byte, 4096)
+	for i := range content {
+		content[i] = byte(i)
+	}
+	msg := &report.Message{
+		To:      *to,
+		Subject: "Prueba de correo — ConcreTrack",
+		Body:    "Este es un correo de prueba con tildes y eñes: ensayo de compresión.\n",
+		Attachments: []report.Attachment{
+			{Filename: "prueba.bin", ContentType: "application/octet-stream", Content: content},
+		},
+	}
+	if err := mail.NewSMTPSender(config).Send(context.Background(), msg); err != nil {
+		log.Fatalf("error al enviar el correo: %v", err)
+	}
+	log.Printf("correo de prueba enviado a %s vía %s", *to, config.Host)
+}
@@ -0,0 +1,225 @@
+package application
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/project"
+	"github.com/wcharczuk/go-chart"
+	"github.com/wcharczuk/go-chart/drawing"
+)
+
+// Image formats control charts are rendered in.
+const (
+	ChartPNG = "png"
+	ChartSVG = "svg"
+)
+
+type ControlChartsService struct {
+	projectService *project.Service
+}
+
+func NewControlChartsService(projectService *project.Service) *ControlChartsService {
+	return &ControlChartsService{projectService: projectService}
+}
+
+// ChartImage is a rendered chart and its content type.
+type ChartImage struct {
+	ContentType string
+	File        []byte
+}
+
+func (c *ControlChartsService) GetControlCharts(filter project.ControlFilter) ([]*project.ControlChart, error) {
+	return c.projectService.GetControlCharts(filter)
+}
+
+// RenderControlChart draws the individuals or moving range chart of the
+// single group matching filter, marking the points that break a rule.
+func (c *ControlChartsService) RenderControlChart(filter project.ControlFilter, which, format string) (*ChartImage, error) {
+	if which == "" {
+		which = project.ChartIndividuals
+	}
+	if format == "" {
+		format = ChartPNG
+	}
+	v := &domain.Validator{}
+	v.Check(which == project.ChartIndividuals || which == project.ChartMovingRange, "chart", "must be individuals or moving_range")
+	v.Check(format == ChartPNG || format == ChartSVG, "format", "must be json, png or svg")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	charts, err := c.projectService.GetControlCharts(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(charts) != 1 {
+		v.Check(false, "group", fmt.Sprintf("the filter matches %d groups; narrow it down to one with supplier or mix_design_code", len(charts)))
+		return nil, v.Err()
+	}
+	cc := charts[0]
+	if cc.Individuals == nil {
+		v.Check(false, "group", "needs at least 2 strength tests")
+		return nil, v.Err()
+	}
+	if which == project.ChartMovingRange && len(cc.Points) < 3 {
+		v.Check(false, "group", "needs at least 3 strength tests for the moving range chart")
+		return nil, v.Err()
+	}
+
+	graph := controlChartGraph(cc, which)
+	renderer, contentType := chart.PNG, "image/png"
+	if format == ChartSVG {
+		renderer, contentType = chart.SVG, "image/svg+xml"
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err := graph.Render(renderer, buf); err != nil {
+		return nil, err
+	}
+	return &ChartImage{ContentType: contentType, File: buf.Bytes()}, nil
+}
+
+func controlChartGraph(cc *project.ControlChart, which string) *chart.Chart {
+	// The moving range chart has no value for the first point.
+	first := 0
+	limits := cc.Individuals
+	value := func(p project.ControlPoint) float64 { return p.Value }
+	title := fmt.Sprintf("Carta X̄ — %s", groupTitle(cc))
+	yName := "Resistencia (PSI)"
+	if which == project.ChartMovingRange {
+		first = 1
+		limits = cc.MovingRange
+		value = func(p project.ControlPoint) float64 { return *p.MovingRange }
+		title = fmt.Sprintf("Carta de rangos móviles — %s", groupTitle(cc))
+		yName = "Rango móvil (PSI)"
+	}
+
+	flagged := map[int]bool{}
+	for _, violation := range cc.Violations {
+		if violation.Chart == which {
+			flagged[violation.Index] = true
+		}
+	}
+
+	var xValues, yValues, xFlagged, yFlagged []float64
+	minY, maxY := limits.Lower, limits.Upper
+	for i := first; i < len(cc.Points); i++ {
+		y := value(cc.Points[i])
+		xValues = append(xValues, float64(i))
+		yValues = append(yValues, y)
+		if flagged[i] {
+			xFlagged = append(xFlagged, float64(i))
+			yFlagged = append(yFlagged, y)
+		}
+		if y < minY {
+			minY = y
+		}
+		if y > maxY {
+			maxY = y
+		}
+	}
+	margin := (maxY - minY) * 0.1
+	if margin == 0 {
+		// Every value and limit is equal; go-chart cannot draw an empty range.
+		margin = math.Max(math.Abs(maxY)*0.1, 1)
+	}
+	rangeMin := minY - margin
+	if which == project.ChartMovingRange {
+		rangeMin = 0
+	}
+	xRange := []float64{xValues[0], xValues[len(xValues)-1]}
+
+	limitLine := func(name string, y float64, dashed bool) chart.ContinuousSeries {
+		style := chart.Style{Show: true, StrokeWidth: 1.5, StrokeColor: chart.ColorRed}
+		if dashed {
+			style.StrokeDashArray = []float64{5, 5}
+		} else {
+			style.StrokeColor = chart.ColorAlternateGray
+		}
+		return chart.ContinuousSeries{Name: name, XValues: xRange, YValues: []float64{y, y}, Style: style}
+	}
+
+	series := []chart.Series{
+		chart.ContinuousSeries{
+			Name:    "Ensayos",
+			XValues: xValues,
+			YValues: yValues,
+			Style:   chart.Style{Show: true, StrokeWidth: 2, DotWidth: 3},
+		},
+		limitLine(fmt.Sprintf("LC %.0f", limits.Center), limits.Center, false),
+		limitLine(fmt.Sprintf("LSC %.0f", limits.Upper), limits.Upper, true),
+	}
+	if which == project.ChartIndividuals {
+		series = append(series, limitLine(fmt.Sprintf("LIC %.0f", limits.Lower), limits.Lower, true))
+	}
+	if len(xFlagged) > 0 {
+		series = append(series, chart.ContinuousSeries{
+			Name:    "Fuera de control",
+			XValues: xFlagged,
+			YValues: yFlagged,
+			Style: chart.Style{
+				Show:        true,
+				StrokeWidth: chart.Disabled,
+				StrokeColor: drawing.ColorRed,
+				DotWidth:    6,
+				DotColor:    drawing.ColorRed,
+			},
+		})
+	}
+
+	graph := &chart.Chart{
+		Width:  1280,
+		Height: 720,
+		Title:  title,
+		TitleStyle: chart.Style{
+			Show:        true,
+			FontSize:    20,
+			StrokeColor: chart.ColorBlack,
+		},
+		Background: chart.Style{
+			Padding: chart.Box{Top: 40, Left: 60, Right: 20},
+		},
+		XAxis: chart.XAxis{
+			Name:      "Fecha de toma",
+			NameStyle: chart.Style{Show: true, FontSize: 14},
+			Style:     chart.Style{Show: true},
+			ValueFormatter: func(v interface{}) string {
+				idx := int(v.(float64))
+				if idx < 0 || idx >= len(cc.Points) {
+					return ""
+				}
+				return cc.Points[idx].Date.Format("2006-01-02")
+			},
+		},
+		YAxis: chart.YAxis{
+			Name:      yName,
+			NameStyle: chart.Style{Show: true, FontSize: 14},
+			Style:     chart.Style{Show: true},
+			Range: &chart.ContinuousRange{
+				Min: rangeMin,
+				Max: maxY + margin,
+			},
+			GridMajorStyle: chart.Style{
+				Show:        true,
+				StrokeColor: chart.ColorAlternateGray,
+				StrokeWidth: 0.5,
+			},
+		},
+		Series: series,
+	}
+	graph.Elements = []chart.Renderable{chart.Legend(graph)}
+	return graph
+}
+
+func groupTitle(cc *project.ControlChart) string {
+	group := cc.Group
+	if group == "" {
+		group = "sin especificar"
+	}
+	if cc.GroupBy == project.ControlBySupplier {
+		return "Proveedor " + group
+	}
+	return "Mezcla " + group
+}
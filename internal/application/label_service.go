@@ -0,0 +1,149 @@
+package application
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/family"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/project"
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+)
+
+// Barcode formats printed on labels.
+const (
+	LabelCode128 = "code128"
+	LabelQR      = "qr"
+)
+
+var labelTmpl = template.Must(template.ParseFiles(filepath.Join(resourcesPath(), "label_template", "labels.html")))
+
+type LabelsService struct {
+	projectsRepo  project.Repository
+	familyService *family.Service
+}
+
+func NewLabelsService(repo project.Repository, familyService *family.Service) *LabelsService {
+	return &LabelsService{projectsRepo: repo, familyService: familyService}
+}
+
+type labelSheet struct {
+	CompanyName string
+	ProjectName string
+	SamplePlace string
+	Format      string
+	Labels      []label
+}
+
+type label struct {
+	MemberID      int
+	FractureDate  string
+	Code          string
+	BarcodeBase64 string
+}
+
+// GenerateFamilyLabels builds a PDF sheet with one label per member of the
+// family, each with a barcode of member.LabelCode in the given format.
+func (l *LabelsService) GenerateFamilyLabels(projectID, familyID int, format string) (*Report, error) {
+	if format == "" {
+		format = LabelCode128
+	}
+	if format != LabelCode128 && format != LabelQR {
+		v := &domain.Validator{}
+		v.Check(false, "format", "must be code128 or qr")
+		return nil, v.Err()
+	}
+
+	project, err := l.projectsRepo.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	family, err := l.familyService.GetProjectFamily(projectID, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := labelSheet{
+		CompanyName: companyName,
+		ProjectName: project.Name,
+		SamplePlace: family.SamplePlace,
+		Format:      format,
+	}
+	for _, m := range family.Members {
+		code := member.LabelCode(m, projectID)
+		image, err := encodeBarcode(code, format)
+		if err != nil {
+			return nil, err
+		}
+		fractureDate := "—"
+		if m.DateOfFracture != nil {
+			fractureDate = m.DateOfFracture.Format("2006-01-02")
+		}
+		sheet.Labels = append(sheet.Labels, label{
+			MemberID:      m.ID,
+			FractureDate:  fractureDate,
+			Code:          code,
+			BarcodeBase64: image,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := labelTmpl.Execute(&buf, sheet); err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp("", "etiquetas-*.pdf")
+	if err != nil {
+		return nil, err
+	}
+	outputPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(outputPath)
+	if err := htmlToPDFWithWK(buf.Bytes(), outputPath, "Portrait"); err != nil {
+		return nil, err
+	}
+	pdfBytes, err := os.ReadFile(outputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := fmt.Sprintf("Etiquetas %v-%v.pdf", project.Name, family.ID)
+	return &Report{Filename: filename, File: pdfBytes}, nil
+}
+
+// encodeBarcode renders code as a base64 PNG, sized for print.
+func encodeBarcode(code, format string) (string, error) {
+	var (
+		bc  barcode.Barcode
+		err error
+	)
+	switch format {
+	case LabelQR:
+		bc, err = qr.Encode(code, qr.M, qr.Auto)
+		if err == nil {
+			bc, err = barcode.Scale(bc, 200, 200)
+		}
+	default:
+		bc, err = code128.Encode(code)
+		if err == nil {
+			bc, err = barcode.Scale(bc, bc.Bounds().Dx()*3, 80)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, bc); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
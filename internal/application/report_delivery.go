@@ -0,0 +1,178 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/family"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/project"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/report"
+)
+
+// mailTmpl defines the "subject" and "body" of the email a report is sent
+// with.
+var mailTmpl = template.Must(template.ParseFiles(filepath.Join(resourcesPath(), "mail_template", "report.txt")))
+
+// SendIssuedReport emails an issued report of a family of the project. With
+// emails, it goes to those recipients again even if they already got it;
+// otherwise to every recipient it was not delivered to yet. Failures are
+// recorded per recipient rather than returned.
+func (r *ReportsService) SendIssuedReport(ctx context.Context, projectID, familyID, reportID int, emails []string) (*report.Issued, error) {
+	if r.sender == nil {
+		return nil, report.ErrMailNotConfigured
+	}
+	project, err := r.projectsRepo.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	family, err := r.familyService.GetProjectFamily(projectID, familyID)
+	if err != nil {
+		return nil, err
+	}
+	issued, err := r.issuedWithFile(familyID, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if issued.Superseded {
+		return nil, fmt.Errorf("report %d was superseded by a result correction, issue a new one: %w", reportID, domain.ErrConflict)
+	}
+
+	recipients, err := selectRecipients(issued.Recipients, emails)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.deliver(ctx, project, family, issued, recipients); err != nil {
+		return nil, err
+	}
+	return r.reportRepo.GetIssuedByID(reportID)
+}
+
+// GetIssuedReport returns an issued report of a family of the project with
+// its file, as it was handed out.
+func (r *ReportsService) GetIssuedReport(projectID, familyID, reportID int) (*report.Issued, error) {
+	if _, err := r.familyService.GetProjectFamily(projectID, familyID); err != nil {
+		return nil, err
+	}
+	return r.issuedWithFile(familyID, reportID)
+}
+
+// issuedWithFile loads an issued report of the family and its file. It
+// wraps domain.ErrConflict when the report has no file kept.
+func (r *ReportsService) issuedWithFile(familyID, reportID int) (*report.Issued, error) {
+	issued, err := r.reportRepo.GetIssuedByID(reportID)
+	if err != nil {
+		return nil, err
+	}
+	if issued.FamilyID != familyID {
+		return nil, fmt.Errorf("issued report %d: %w", reportID, domain.ErrNotFound)
+	}
+	file, err := r.reportRepo.GetIssuedFile(reportID)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, fmt.Errorf("report %d was issued before reports were kept, issue a new one: %w", reportID, domain.ErrConflict)
+	}
+	issued.File = file
+	return issued, nil
+}
+
+// selectRecipients picks the recipients named by emails, or those the
+// report is not delivered to yet when emails is empty.
+func selectRecipients(recipients []report.Recipient, emails []string) ([]report.Recipient, error) {
+	if len(emails) == 0 {
+		var pending []report.Recipient
+		for _, rcpt := range recipients {
+			if rcpt.Status != report.DeliverySent {
+				pending = append(pending, rcpt)
+			}
+		}
+		return pending, nil
+	}
+
+	byEmail := make(map[string]report.Recipient, len(recipients))
+	for _, rcpt := range recipients {
+		byEmail[strings.ToLower(rcpt.Email)] = rcpt
+	}
+	v := &domain.Validator{}
+	selected := make([]report.Recipient, 0, len(emails))
+	seen := make(map[string]bool, len(emails))
+	for i, email := range emails {
+		key := strings.ToLower(strings.TrimSpace(email))
+		rcpt, ok := byEmail[key]
+		v.Check(ok, fmt.Sprintf("emails[%d]", i), "is not a recipient of the report")
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		selected = append(selected, rcpt)
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+// deliver sends issued to each recipient and records the outcome. Only
+// failing to record it is returned.
+func (r *ReportsService) deliver(ctx context.Context, project *project.Project, family *family.Family, issued *report.Issued, recipients []report.Recipient) error {
+	for _, rcpt := range recipients {
+		msg, err := reportMessage(project, family, issued, rcpt)
+		if err == nil {
+			err = r.sender.Send(ctx, msg)
+		}
+		if err := r.reportRepo.RecordDelivery(issued.ID, rcpt.Email, err, time.Now().UTC()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reportMessage(project *project.Project, family *family.Family, issued *report.Issued, rcpt report.Recipient) (*report.Message, error) {
+	data := struct {
+		Name         string
+		Client       string
+		Project      string
+		SamplePlace  string
+		DateOfEntry  string
+		Filename     string
+		IssuedAt     string
+		Company      string
+		CompanyPhone string
+	}{
+		Name:         rcpt.Name,
+		Client:       project.Client.Name,
+		Project:      project.Name,
+		SamplePlace:  family.SamplePlace,
+		DateOfEntry:  family.DateOfEntry.Format("2006-01-02"),
+		Filename:     issued.Filename,
+		IssuedAt:     issued.IssuedAt.Local().Format("2006-01-02 15:04"),
+		Company:      companyName,
+		CompanyPhone: companyPhone,
+	}
+
+	var subject, body bytes.Buffer
+	if err := mailTmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := mailTmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return nil, err
+	}
+	return &report.Message{
+		ToName:  rcpt.Name,
+		To:      rcpt.Email,
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    body.String(),
+		Attachments: []report.Attachment{{
+			Filename:    issued.Filename,
+			ContentType: "application/pdf",
+			Content:     issued.File,
+		}},
+	}, nil
+}
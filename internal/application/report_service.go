@@ -2,6 +2,7 @@ package application
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"html/template"
@@ -12,11 +13,16 @@ import (
 	"path/filepath"
 	"runtime"
 	"slices"
+	"strings"
 	"time"
 
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/client"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/family"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/project"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/report"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/user"
 	"github.com/wcharczuk/go-chart"
 )
 
@@ -25,21 +31,51 @@ var (
 	companyAddress = "Calle tal #tal-tal frente a tal"
 	companyPhone   = "3051234567"
 	tmpl           = template.Must(template.ParseFiles(templatePath()))
+	// fractureSketches holds the base64 SVG drawing of each fracture type,
+	// by code.
+	fractureSketches = loadFractureSketches()
 )
 
 func templatePath() string {
+	return filepath.Join(resourcesPath(), "report_template", "template.html")
+}
+
+func resourcesPath() string {
 	_, file, _, _ := runtime.Caller(0)
 	base := filepath.Dir(file)
-	return filepath.Join(base, "..", "..", "resources", "report_template", "template.html")
+	return filepath.Join(base, "..", "..", "resources")
+}
+
+func loadFractureSketches() map[string]string {
+	sketches := make(map[string]string, len(member.FractureTypes))
+	for _, t := range member.FractureTypes {
+		if t.Sketch == "" {
+			continue
+		}
+		svg, err := os.ReadFile(filepath.Join(resourcesPath(), "fracture_types", t.Sketch))
+		if err != nil {
+			log.Printf("[loadFractureSketches] Missing sketch for fracture type %s. err=%v", t.Code, err)
+			continue
+		}
+		sketches[t.Code] = base64.StdEncoding.EncodeToString(svg)
+	}
+	return sketches
 }
 
 type ReportsService struct {
-	projectsRepo project.Repository
+	projectsRepo  project.Repository
+	familyService *family.Service
+	clientService *client.Service
+	memberService *member.Service
+	reportRepo    report.Repository
+	// sender is nil when mail delivery is not configured.
+	sender report.Sender
 }
 
 type Report struct {
-	Filename string
-	File     []byte
+	Filename   string
+	File       []byte
+	Recipients []client.Contact
 }
 
 type ReportMember struct {
@@ -50,39 +86,103 @@ type ReportMember struct {
 	SamplePlace      string
 	DateOfEntry      string
 	AgeDays          int
-	DiameterCM       float64
-	LengthCM         float64
+	Dimensions       string
 	AreaCM2          string
-	AdjustmentFactor int
+	AdjustmentFactor string
+	Density          string
 	StrengthKGCM2    string
 	StrengthPSI      string
 	DesignMPA        string
 	DesignPSI        string
 	ObtainedPercent  string
 	FailureShape     string
-    Perpendicularity string
+	FailureSketch    string
+	Perpendicularity string
+	Capping          string
+	Planeness        string
+	// CurveChart is the base64 PNG of the load curve, if one was recorded.
+	CurveChart string
+}
+
+// ReportField is a labelled value printed in the report. Fields without a
+// value are left out.
+type ReportField struct {
+	Label string
+	Value string
 }
 
-func NewReportsService(repo project.Repository) *ReportsService {
-	return &ReportsService{projectsRepo: repo}
+func NewReportsService(repo project.Repository, familyService *family.Service, clientService *client.Service, memberService *member.Service, reportRepo report.Repository, sender report.Sender) *ReportsService {
+	return &ReportsService{projectsRepo: repo, familyService: familyService, clientService: clientService, memberService: memberService, reportRepo: reportRepo, sender: sender}
 }
 
-func (r *ReportsService) GenerateReportForOneFamily(projectID int, familyID int) (*Report, error) {
+// GenerateReportForOneFamily builds the PDF report of a family addressed to
+// the given client contacts, or to those flagged to receive reports when
+// recipientIDs is empty, as a preview: nothing is recorded. IssueReport
+// hands it out.
+func (r *ReportsService) GenerateReportForOneFamily(projectID int, familyID int, recipientIDs []int) (*Report, error) {
+	return r.buildReport(projectID, familyID, recipientIDs)
+}
+
+// IssueReport builds the report of a family like GenerateReportForOneFamily
+// and records it as issued by the authenticated user, keeping its file.
+// SendIssuedReport emails it.
+func (r *ReportsService) IssueReport(ctx context.Context, projectID int, familyID int, recipientIDs []int) (*report.Issued, error) {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+
+	built, err := r.buildReport(projectID, familyID, recipientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	issued := &report.Issued{
+		ProjectID: projectID,
+		FamilyID:  familyID,
+		Filename:  built.Filename,
+		IssuedBy:  &u.ID,
+		IssuedAt:  time.Now().UTC(),
+		File:      built.File,
+	}
+	for _, c := range built.Recipients {
+		contactID := c.ID
+		issued.Recipients = append(issued.Recipients, report.Recipient{ContactID: &contactID, Name: c.Name, Email: c.Email})
+	}
+	if err := r.reportRepo.SaveIssued(issued); err != nil {
+		return nil, err
+	}
+	return r.reportRepo.GetIssuedByID(issued.ID)
+}
+
+func (r *ReportsService) buildReport(projectID int, familyID int, recipientIDs []int) (*Report, error) {
 	project, err := r.projectsRepo.GetProjectByID(projectID)
 
 	if err != nil {
 		return nil, err
 	}
 
-	var family *family.Family
-	for _, p := range project.Families {
-		if familyID == p.ID {
-			family = &p
-			break
-		}
+	family, err := r.familyService.GetProjectFamily(projectID, familyID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	recipients, err := r.clientService.ReportRecipients(project.ClientID, recipientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	memberIDs := make([]int, len(family.Members))
+	for i, m := range family.Members {
+		memberIDs[i] = m.ID
+	}
+	curves, err := r.memberService.GetCurves(memberIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	data := r.generateReportData(project, family)
+	data := r.generateReportData(project, family, recipients, curves)
 
 	var buf bytes.Buffer
 	err = tmpl.Execute(&buf, data)
@@ -99,7 +199,7 @@ func (r *ReportsService) GenerateReportForOneFamily(projectID int, familyID int)
 	outputPath := tmp.Name()
 	tmp.Close()
 	defer os.Remove(outputPath)
-	if err := htmlToPDFWithWK(html, outputPath); err != nil {
+	if err := htmlToPDFWithWK(html, outputPath, "Landscape"); err != nil {
 		return nil, err
 	}
 	pdfBytes, err := os.ReadFile(outputPath)
@@ -109,7 +209,24 @@ func (r *ReportsService) GenerateReportForOneFamily(projectID int, familyID int)
 
 	filename := fmt.Sprintf("Reporte %v-%v-%v.pdf", project.Name, "Lugar de toma", time.Now().Format("2006-01-02 15:04:05"))
 
-	return &Report{Filename: filename, File: pdfBytes}, nil
+	return &Report{Filename: filename, File: pdfBytes, Recipients: recipients}, nil
+}
+
+// GetIssuedReports lists the reports issued for a family of the project,
+// newest first, flagging those superseded by a result correction.
+func (r *ReportsService) GetIssuedReports(projectID int, familyID int, req domain.PageRequest) (*domain.Page[*report.Issued], error) {
+	if err := req.Prepare(); err != nil {
+		return nil, err
+	}
+	if _, err := r.familyService.GetProjectFamily(projectID, familyID); err != nil {
+		return nil, err
+	}
+
+	issued, total, err := r.reportRepo.GetIssued(familyID, req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(issued, total, req, func(i *report.Issued) int { return i.ID }), nil
 }
 
 func (r *ReportsService) generateReportsChart(members []member.Member, threshold float64) string {
@@ -226,7 +343,80 @@ func (r *ReportsService) generateReportsChart(members []member.Member, threshold
 	return encoded
 }
 
-func (r *ReportsService) generateReportData(project *project.Project, family *family.Family) interface{} {
+// maxChartCurvePoints bounds the samples plotted per load curve; denser
+// curves are thinned out, always keeping the peak.
+const maxChartCurvePoints = 400
+
+// generateCurveChart plots the load curve of a specimen as a small base64
+// PNG, or returns "" when there is none.
+func generateCurveChart(c *member.Curve) string {
+	if c == nil || len(c.Points) < 2 {
+		return ""
+	}
+
+	step := (len(c.Points) + maxChartCurvePoints - 1) / maxChartCurvePoints
+	peak := 0
+	for i, p := range c.Points {
+		if p.Load > c.Points[peak].Load {
+			peak = i
+		}
+	}
+	var xValues, yValues []float64
+	for i, p := range c.Points {
+		if i%step == 0 || i == peak || i == len(c.Points)-1 {
+			xValues = append(xValues, p.X)
+			yValues = append(yValues, p.Load)
+		}
+	}
+
+	xName := "Desplazamiento (mm)"
+	if c.Axis == member.CurveAxisTime {
+		xName = "Tiempo (s)"
+	}
+
+	graph := chart.Chart{
+		Width:  480,
+		Height: 300,
+		Background: chart.Style{
+			Padding: chart.Box{Top: 20, Left: 20, Right: 20, Bottom: 10},
+		},
+		XAxis: chart.XAxis{
+			Name:      xName,
+			NameStyle: chart.Style{Show: true, FontSize: 10},
+			Style:     chart.Style{Show: true, FontSize: 8},
+		},
+		YAxis: chart.YAxis{
+			Name:      "Carga (KN)",
+			NameStyle: chart.Style{Show: true, FontSize: 10},
+			Style:     chart.Style{Show: true, FontSize: 8},
+			Range: &chart.ContinuousRange{
+				Min: 0,
+				Max: c.PeakLoad * 1.1,
+			},
+			GridMajorStyle: chart.Style{
+				Show:        true,
+				StrokeColor: chart.ColorAlternateGray,
+				StrokeWidth: 0.5,
+			},
+		},
+		Series: []chart.Series{
+			chart.ContinuousSeries{
+				XValues: xValues,
+				YValues: yValues,
+				Style:   chart.Style{Show: true, StrokeWidth: 2},
+			},
+		},
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	if err := graph.Render(chart.PNG, buf); err != nil {
+		log.Printf("[generateCurveChart] Error for member %d: %v", c.MemberID, err)
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func (r *ReportsService) generateReportData(project *project.Project, family *family.Family, recipients []client.Contact, curves map[int]*member.Curve) interface{} {
 	data := struct {
 		Company struct {
 			Name    string
@@ -234,19 +424,33 @@ func (r *ReportsService) generateReportData(project *project.Project, family *fa
 			Phone   string
 		}
 		Client struct {
-			Name string
-			ID   int
+			Name    string
+			ID      int
+			TaxID   string
+			Address string
+			City    string
 		}
 		Project struct {
-			Name       string
-			ReportDate string
+			Name                string
+			ReportDate          string
+			Address             string
+			Coordinates         string
+			Contractor          string
+			SupervisingEngineer string
+			ContractNumber      string
+			Period              string
 		}
 		Family struct {
 			Name             string
 			DesignResistance float64
 			DateOfEntry      *time.Time
+			Sampling         []ReportField
+			SpecimenTitle    string
+			StrengthLabel    string
 		}
+		Recipients  []client.Contact
 		Members     []ReportMember
+		HasCurves   bool
 		ChartBase64 string
 	}{}
 
@@ -255,48 +459,238 @@ func (r *ReportsService) generateReportData(project *project.Project, family *fa
 	data.Company.Phone = companyPhone
 	data.Client.Name = project.Client.Name
 	data.Client.ID = project.ClientID
+	data.Client.TaxID = project.Client.TaxID
+	data.Client.Address = project.Client.Address
+	data.Client.City = project.Client.City
+	data.Recipients = recipients
 	data.Project.Name = project.Name
 	data.Project.ReportDate = time.Now().Format("2006-01-02 15:04:05")
+	data.Project.Address = joinNonEmpty(", ", project.Address, project.City)
+	if project.Latitude != nil && project.Longitude != nil {
+		data.Project.Coordinates = fmt.Sprintf("%.6f, %.6f", *project.Latitude, *project.Longitude)
+	}
+	data.Project.Contractor = project.Contractor
+	data.Project.SupervisingEngineer = project.SupervisingEngineer
+	data.Project.ContractNumber = project.ContractNumber
+	data.Project.Period = projectPeriod(project)
 	data.Family.Name = family.SamplePlace
+	data.Family.Sampling = samplingFields(family)
+	data.Family.SpecimenTitle = specimenTitles[family.SpecimenType]
+	data.Family.StrengthLabel = "Resistencia obtenida"
+	if family.Geometry().Flexural() {
+		data.Family.StrengthLabel = "Módulo de rotura"
+	}
+	var fractured []member.Member
 	for _, v := range family.Members {
-		if v.IsReported != nil && *v.IsReported {
-			cilynderArea := math.Pi * math.Pow(family.Radius, 2)
-			StrengthKGCM2 := (*v.Result / cilynderArea) * 1 * 102
-			StrengthPSI := StrengthKGCM2 / 0.07
+		if v.Result != nil && v.FracturedAt != nil {
+			fractured = append(fractured, v)
+		}
+		if v.IsReported != nil && *v.IsReported && v.Result != nil {
+			geometry := family.MemberGeometry(v)
+			area := "—"
+			if !geometry.Flexural() {
+				area = fmt.Sprintf("%.2f", geometry.Area())
+			}
+			StrengthKGCM2 := geometry.StrengthKGCM2(*v.Result)
+			StrengthPSI := family.MemberStrengthPSI(v)
+			density := "—"
+			if d := family.MemberDensity(v); d != nil {
+				density = fmt.Sprintf("%.0f", *d)
+			}
+			entry, fracturedAt, age := memberDates(family, v)
+			shape, sketch := describeFracture(v)
+			operative := ""
+			if v.Operative != nil {
+				operative = fmt.Sprintf("%s %s", v.Operative.FirstName, v.Operative.LastName)
+			}
 			data.Members = append(data.Members, ReportMember{
 				SamplePlace:      family.SamplePlace,
-				DateOfEntry:      v.DateOfFracture.AddDate(0, 0, -*v.FractureDays).Format(("2006-01-02")),
-				AgeDays:          *v.FractureDays,
-				DiameterCM:       family.Radius * 2,
-				LengthCM:         family.Height,
-				AreaCM2:          fmt.Sprintf("%.2f", cilynderArea),
-				AdjustmentFactor: 1,
+				DateOfEntry:      entry,
+				AgeDays:          age,
+				Dimensions:       describeGeometry(geometry),
+				AreaCM2:          area,
+				AdjustmentFactor: fmt.Sprintf("%.2f", geometry.CorrectionFactor()),
+				Density:          density,
 				StrengthKGCM2:    fmt.Sprintf("%.2f", StrengthKGCM2),
 				StrengthPSI:      fmt.Sprintf("%.2f", StrengthPSI),
-				DesignMPA:        fmt.Sprintf("%.2f", family.DesignResistance / 145.0377),
+				DesignMPA:        fmt.Sprintf("%.2f", family.DesignResistanceMPA()),
 				DesignPSI:        fmt.Sprintf("%.2f", family.DesignResistance),
 				ObtainedPercent:  fmt.Sprintf("%.2f", (StrengthPSI / family.DesignResistance) * 100),
-				FailureShape:     *v.FractureType,
+				FailureShape:     shape,
+				FailureSketch:    sketch,
 				ID:               v.ID,
-				FracturedAt:      v.FracturedAt.Local().Format("2006-01-02"),
+				FracturedAt:      fracturedAt,
 				Result:           *v.Result,
-				Operative:        fmt.Sprintf("%s %s", v.Operative.FirstName, v.Operative.LastName),
-                Perpendicularity: "Si        No",})
+				Operative:        operative,
+				Perpendicularity: yesNo(v.Perpendicular),
+				Capping:          describeCapping(v.CappingMethod),
+				Planeness:        yesNo(v.PlanenessOK),
+				CurveChart:       generateCurveChart(curves[v.ID]),
+			})
+			data.HasCurves = data.HasCurves || curves[v.ID] != nil
 		}
 
 	}
     
-	data.ChartBase64 = r.generateReportsChart(family.Members, family.DesignResistance)
+	data.ChartBase64 = r.generateReportsChart(fractured, family.DesignResistance)
 	return data
 }
 
-func htmlToPDFWithWK(html []byte, outputPath string) error {
+// memberDates returns the sampling and fracture dates of a member and its
+// age in days. The sampling date is worked out from the fracture when
+// possible and falls back to the family's entry date.
+func memberDates(f *family.Family, m member.Member) (entry, fractured string, age int) {
+	entryDate := f.DateOfEntry
+	if m.DateOfFracture != nil && m.FractureDays != nil {
+		entryDate = m.DateOfFracture.AddDate(0, 0, -*m.FractureDays)
+	}
+
+	fractured = "—"
+	var fractureDate *time.Time
+	switch {
+	case m.FracturedAt != nil:
+		fractureDate = m.FracturedAt
+	case m.DateOfFracture != nil:
+		fractureDate = m.DateOfFracture
+	}
+	if fractureDate != nil {
+		fractured = fractureDate.Local().Format("2006-01-02")
+	}
+
+	switch {
+	case m.FractureDays != nil:
+		age = *m.FractureDays
+	case fractureDate != nil && !entryDate.IsZero():
+		age = int(fractureDate.Sub(entryDate).Hours() / 24)
+	}
+	return entryDate.Format("2006-01-02"), fractured, age
+}
+
+// describeFracture returns the failure pattern name of a member and its
+// base64 sketch, if any. Values outside the catalogue are printed as is.
+func describeFracture(m member.Member) (shape, sketch string) {
+	if m.FractureType == nil || *m.FractureType == "" {
+		return "—", ""
+	}
+	t, ok := member.FractureTypeByCode(*m.FractureType)
+	if !ok {
+		return *m.FractureType, ""
+	}
+	return t.Name, fractureSketches[t.Code]
+}
+
+// cappingMethods names the capping methods in the report.
+var cappingMethods = map[string]string{
+	member.CappingSulfur:   "Mortero de azufre",
+	member.CappingNeoprene: "Almohadillas de neopreno",
+	member.CappingGround:   "Pulido",
+	member.CappingNone:     "Sin refrentado",
+}
+
+// yesNo prints a preparation check, "—" when it was not recorded.
+func yesNo(b *bool) string {
+	switch {
+	case b == nil:
+		return "—"
+	case *b:
+		return "Sí"
+	}
+	return "No"
+}
+
+// describeCapping prints a capping method, "—" when it was not recorded.
+func describeCapping(method *string) string {
+	if method == nil {
+		return "—"
+	}
+	if name, ok := cappingMethods[*method]; ok {
+		return name
+	}
+	return *method
+}
+
+// specimenTitles heads the results table for each specimen type.
+var specimenTitles = map[string]string{
+	family.SpecimenCylinder: "Cilindros Ensayados",
+	family.SpecimenCube:     "Cubos Ensayados",
+	family.SpecimenBeam:     "Vigas Ensayadas a Flexión",
+	family.SpecimenCore:     "Núcleos Ensayados",
+}
+
+// describeGeometry prints the dimensions of a specimen in cm.
+func describeGeometry(g family.Geometry) string {
+	switch g.SpecimenType {
+	case family.SpecimenCube:
+		return fmt.Sprintf("%.2f × %.2f × %.2f", g.Side, g.Side, g.Side)
+	case family.SpecimenBeam:
+		return fmt.Sprintf("%.2f × %.2f, luz %.2f", g.Width, g.Depth, g.Span)
+	default:
+		return fmt.Sprintf("Ø %.2f × %.2f", g.Radius*2, g.Height)
+	}
+}
+
+// samplingFields lists the field sampling data of the family in the order
+// it is printed.
+func samplingFields(f *family.Family) []ReportField {
+	measure := func(v *float64, unit string) string {
+		if v == nil {
+			return ""
+		}
+		return fmt.Sprintf("%.1f %s", *v, unit)
+	}
+
+	candidates := []ReportField{
+		{"Elemento estructural", f.StructuralElement},
+		{"Proveedor", f.Supplier},
+		{"Remisión", f.TruckTicket},
+		{"Diseño de mezcla", f.MixDesignCode},
+		{"Asentamiento", measure(f.SlumpCM, "cm")},
+		{"Temperatura del concreto", measure(f.ConcreteTemperatureC, "°C")},
+		{"Temperatura ambiente", measure(f.AmbientTemperatureC, "°C")},
+		{"Técnico de muestreo", f.SamplingTechnician},
+	}
+
+	var fields []ReportField
+	for _, c := range candidates {
+		if strings.TrimSpace(c.Value) != "" {
+			fields = append(fields, c)
+		}
+	}
+	return fields
+}
+
+// projectPeriod formats the start and end dates of the project for the
+// report header, leaving out whichever is missing.
+func projectPeriod(p *project.Project) string {
+	switch {
+	case p.StartDate.IsZero() && p.EndDate.IsZero():
+		return ""
+	case p.EndDate.IsZero():
+		return "Desde " + p.StartDate.Format("2006-01-02")
+	case p.StartDate.IsZero():
+		return "Hasta " + p.EndDate.Format("2006-01-02")
+	default:
+		return p.StartDate.Format("2006-01-02") + " a " + p.EndDate.Format("2006-01-02")
+	}
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, sep)
+}
+
+func htmlToPDFWithWK(html []byte, outputPath, orientation string) error {
 	cmd := exec.Command("wkhtmltopdf",
 		"--enable-local-file-access",
 		"--encoding", "utf-8",
 
-		// Orientación horizontal
-		"--orientation", "Landscape",
+		// Landscape para reportes, Portrait para etiquetas
+		"--orientation", orientation,
 
 		// Tamaño de página (opcional pero recomendado)
 		"--page-size", "A4",
@@ -0,0 +1,93 @@
+// Package alert raises alerts when a recorded result falls short of the
+// design strength, and keeps them in an inbox until they are resolved.
+package alert
+
+import (
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+)
+
+// Alert statuses. An alert is raised open, may be acknowledged while it is
+// looked into, and is closed by resolving it.
+const (
+	StatusOpen         = "open"
+	StatusAcknowledged = "acknowledged"
+	StatusResolved     = "resolved"
+)
+
+// Notification channels and outcomes.
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+
+	NotificationSent   = "sent"
+	NotificationFailed = "failed"
+)
+
+// Rule fires when the strength of a member falls below ThresholdPercent of
+// its family's design strength. Zero ProjectID, SpecimenType and AgeDays
+// match every member.
+type Rule struct {
+	ID               int     `db:"id" json:"id"`
+	Name             string  `db:"name" json:"name"`
+	ThresholdPercent float64 `db:"threshold_percent" json:"threshold_percent"`
+	ProjectID        *int    `db:"project_id" json:"project_id"`
+	SpecimenType     string  `db:"specimen_type" json:"specimen_type"`
+	AgeDays          *int    `db:"age_days" json:"age_days"`
+	// Emails are notified besides the lab managers' inbox; NotifyClient
+	// adds the client contacts that receive reports.
+	Emails       []string  `db:"-" json:"emails"`
+	NotifyClient bool      `db:"notify_client" json:"notify_client"`
+	WebhookURL   string    `db:"webhook_url" json:"webhook_url"`
+	Active       bool      `db:"active" json:"active"`
+	CreatedBy    *int      `db:"created_by" json:"created_by"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+// Alert is a member whose strength broke a rule. Strengths are in PSI.
+type Alert struct {
+	ID               int        `db:"id" json:"id"`
+	RuleID           int        `db:"rule_id" json:"rule_id"`
+	RuleName         string     `db:"rule_name" json:"rule_name"`
+	MemberID         int        `db:"member_id" json:"member_id"`
+	FamilyID         int        `db:"family_id" json:"family_id"`
+	SamplePlace      string     `db:"sample_place" json:"sample_place"`
+	ProjectID        int        `db:"project_id" json:"project_id"`
+	ProjectName      string     `db:"project_name" json:"project_name"`
+	ClientID         int        `db:"client_id" json:"client_id"`
+	Result           float64    `db:"result" json:"result"`
+	StrengthPSI      float64    `db:"strength_psi" json:"strength_psi"`
+	DesignResistance float64    `db:"design_resistance" json:"design_resistance"`
+	Percent          float64    `db:"percent" json:"percent"`
+	AgeDays          *int       `db:"age_days" json:"age_days"`
+	Status           string     `db:"status" json:"status"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	AcknowledgedBy   *int       `db:"acknowledged_by" json:"acknowledged_by"`
+	AcknowledgedAt   *time.Time `db:"acknowledged_at" json:"acknowledged_at"`
+	ResolvedBy       *int       `db:"resolved_by" json:"resolved_by"`
+	ResolvedAt       *time.Time `db:"resolved_at" json:"resolved_at"`
+	ResolutionNote   *string    `db:"resolution_note" json:"resolution_note"`
+	// Notifications are only loaded by GetAlert.
+	Notifications []Notification `db:"-" json:"notifications,omitempty"`
+}
+
+// Notification is an email or webhook sent for an alert.
+type Notification struct {
+	ID      int       `db:"id" json:"id"`
+	AlertID int       `db:"alert_id" json:"alert_id"`
+	Channel string    `db:"channel" json:"channel"`
+	Target  string    `db:"target" json:"target"`
+	Status  string    `db:"status" json:"status"`
+	Error   *string   `db:"error" json:"error"`
+	SentAt  time.Time `db:"sent_at" json:"sent_at"`
+}
+
+// Filter narrows the alert inbox. Zero values match every alert.
+type Filter struct {
+	Status    string
+	ProjectID int
+	RuleID    int
+	MemberID  int
+	domain.PageRequest
+}
@@ -0,0 +1,95 @@
+package alert
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/family"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
+)
+
+// ResultRecorded checks a freshly recorded result against the active rules
+// and raises an alert for each rule it breaks, unless that rule already has
+// an unresolved alert for the member. The result is already stored, so
+// failures are logged instead of failing the request. Notifications are sent
+// in the background.
+func (s *Service) ResultRecorded(ctx context.Context, m *member.Member) {
+	f, err := s.familyService.GetFamilyByID(m.FamilyID)
+	if err != nil {
+		log.Printf("[alert.ResultRecorded] Failed loading family %d of member %d. err=%v", m.FamilyID, m.ID, err)
+		return
+	}
+	var recorded *member.Member
+	for i := range f.Members {
+		if f.Members[i].ID == m.ID {
+			recorded = &f.Members[i]
+		}
+	}
+	// Beams give a modulus of rupture, which is not comparable with f'c.
+	if recorded == nil || recorded.Result == nil || f.DesignResistance <= 0 ||
+		f.Geometry().Flexural() || !f.MemberGeometry(*recorded).Valid() {
+		return
+	}
+
+	rules, err := s.repo.GetActiveRules()
+	if err != nil {
+		log.Printf("[alert.ResultRecorded] Failed loading rules for member %d. err=%v", m.ID, err)
+		return
+	}
+
+	strength := f.MemberStrengthPSI(*recorded)
+	percent := strength / f.DesignResistance * 100
+	for _, rule := range rules {
+		if !rule.matches(f, recorded, percent) {
+			continue
+		}
+		unresolved, err := s.repo.HasUnresolvedAlert(rule.ID, m.ID)
+		if err != nil {
+			log.Printf("[alert.ResultRecorded] Failed checking alerts of rule %d for member %d. err=%v", rule.ID, m.ID, err)
+			continue
+		}
+		if unresolved {
+			continue
+		}
+
+		a := &Alert{
+			RuleID:           rule.ID,
+			MemberID:         m.ID,
+			FamilyID:         f.ID,
+			ProjectID:        f.ProjectID,
+			Result:           *recorded.Result,
+			StrengthPSI:      strength,
+			DesignResistance: f.DesignResistance,
+			Percent:          percent,
+			AgeDays:          recorded.FractureDays,
+			Status:           StatusOpen,
+			CreatedAt:        time.Now().UTC(),
+		}
+		if err := s.repo.SaveAlert(a); err != nil {
+			log.Printf("[alert.ResultRecorded] Failed saving alert of rule %d for member %d. err=%v", rule.ID, m.ID, err)
+			continue
+		}
+		saved, err := s.repo.GetAlert(a.ID)
+		if err != nil {
+			log.Printf("[alert.ResultRecorded] Failed loading alert %d. err=%v", a.ID, err)
+			continue
+		}
+		go s.notify(context.WithoutCancel(ctx), saved, rule)
+	}
+}
+
+// matches reports whether the member, of strength percent of the design,
+// breaks the rule.
+func (r *Rule) matches(f *family.Family, m *member.Member, percent float64) bool {
+	if r.ProjectID != nil && *r.ProjectID != f.ProjectID {
+		return false
+	}
+	if r.SpecimenType != "" && r.SpecimenType != f.SpecimenType {
+		return false
+	}
+	if r.AgeDays != nil && (m.FractureDays == nil || *m.FractureDays != *r.AgeDays) {
+		return false
+	}
+	return percent < r.ThresholdPercent
+}
@@ -0,0 +1,108 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/report"
+)
+
+// notifyTimeout bounds sending every notification of an alert.
+const notifyTimeout = 2 * time.Minute
+
+// EventAlertRaised is the event of the webhook payloads.
+const EventAlertRaised = "alert.raised"
+
+// WebhookPayload is posted to the webhook of a rule when it raises an alert.
+type WebhookPayload struct {
+	Event string `json:"event"`
+	Alert *Alert `json:"alert"`
+}
+
+// notify emails the rule's addresses, and the client's report recipients
+// when the rule says so, then calls its webhook, recording each outcome.
+func (s *Service) notify(ctx context.Context, a *Alert, rule *Rule) {
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+
+	type recipient struct{ name, email string }
+	var recipients []recipient
+	seen := map[string]bool{}
+	add := func(name, email string) {
+		key := strings.ToLower(email)
+		if !seen[key] {
+			seen[key] = true
+			recipients = append(recipients, recipient{name, email})
+		}
+	}
+	for _, email := range rule.Emails {
+		add("", email)
+	}
+	if rule.NotifyClient && a.ClientID > 0 {
+		contacts, err := s.clientService.ReportRecipients(a.ClientID, nil)
+		if err != nil {
+			log.Printf("[alert.notify] Failed loading contacts of client %d for alert %d. err=%v", a.ClientID, a.ID, err)
+		}
+		for _, c := range contacts {
+			add(c.Name, c.Email)
+		}
+	}
+
+	for _, rcpt := range recipients {
+		err := report.ErrMailNotConfigured
+		if s.sender != nil {
+			err = s.sender.Send(ctx, alertMessage(a, rule, rcpt.name, rcpt.email))
+		}
+		s.recordNotification(a, ChannelEmail, rcpt.email, err)
+	}
+	if rule.WebhookURL != "" {
+		err := s.webhook.Post(ctx, rule.WebhookURL, WebhookPayload{Event: EventAlertRaised, Alert: a})
+		s.recordNotification(a, ChannelWebhook, rule.WebhookURL, err)
+	}
+}
+
+func (s *Service) recordNotification(a *Alert, channel, target string, sendErr error) {
+	n := &Notification{AlertID: a.ID, Channel: channel, Target: target, Status: NotificationSent, SentAt: time.Now().UTC()}
+	if sendErr != nil {
+		msg := sendErr.Error()
+		n.Status, n.Error = NotificationFailed, &msg
+	}
+	if err := s.repo.SaveNotification(n); err != nil {
+		log.Printf("[alert.notify] Failed saving %s notification of alert %d. err=%v", channel, a.ID, err)
+	}
+}
+
+func alertMessage(a *Alert, rule *Rule, name, email string) *report.Message {
+	age := "sin edad registrada"
+	if a.AgeDays != nil {
+		age = fmt.Sprintf("%d días", *a.AgeDays)
+	}
+	place := ""
+	if a.SamplePlace != "" {
+		place = " — " + a.SamplePlace
+	}
+
+	var body strings.Builder
+	if name != "" {
+		fmt.Fprintf(&body, "Estimado(a) %s:\n\n", name)
+	}
+	fmt.Fprintf(&body, "Se registró un resultado por debajo del %.0f %% de la resistencia de diseño (regla «%s»).\n\n",
+		rule.ThresholdPercent, rule.Name)
+	fmt.Fprintf(&body, "Proyecto: %s\n", a.ProjectName)
+	fmt.Fprintf(&body, "Muestra: %d%s\n", a.FamilyID, place)
+	fmt.Fprintf(&body, "Espécimen: %d (%s)\n", a.MemberID, age)
+	fmt.Fprintf(&body, "Resistencia obtenida: %.0f PSI\n", a.StrengthPSI)
+	fmt.Fprintf(&body, "Resistencia de diseño: %.0f PSI\n", a.DesignResistance)
+	fmt.Fprintf(&body, "Porcentaje obtenido: %.1f %%\n\n", a.Percent)
+	body.WriteString("El laboratorio revisará el caso y les informará las acciones a seguir.\n")
+
+	return &report.Message{
+		ToName:  name,
+		To:      email,
+		Subject: fmt.Sprintf("Alerta de resistencia baja — %s%s", a.ProjectName, place),
+		Body:    body.String(),
+	}
+}
@@ -0,0 +1,27 @@
+package alert
+
+import "github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+
+type Repository interface {
+	SaveRule(rule *Rule) error
+	UpdateRule(rule *Rule) error
+	// GetRule wraps domain.ErrNotFound when the rule does not exist.
+	GetRule(ID int) (*Rule, error)
+	GetRules(req domain.PageRequest) ([]*Rule, int, error)
+	GetActiveRules() ([]*Rule, error)
+
+	SaveAlert(a *Alert) error
+	// GetAlert loads the alert with its notifications. It wraps
+	// domain.ErrNotFound when the alert does not exist.
+	GetAlert(ID int) (*Alert, error)
+	// GetAlerts lists alerts newest first.
+	GetAlerts(filter Filter) ([]*Alert, int, error)
+	// HasUnresolvedAlert reports whether the rule already has an alert for
+	// the member that is not resolved.
+	HasUnresolvedAlert(ruleID, memberID int) (bool, error)
+	// UpdateAlertStatus stores the acknowledgement or resolution of a. It
+	// wraps domain.ErrConflict when the alert is no longer in fromStatus.
+	UpdateAlertStatus(a *Alert, fromStatus string) error
+
+	SaveNotification(n *Notification) error
+}
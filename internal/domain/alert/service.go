@@ -0,0 +1,221 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/client"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/family"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/report"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/user"
+)
+
+// maxThresholdPercent bounds ThresholdPercent; rules above 100% flag
+// results that pass but with too little margin.
+const maxThresholdPercent = 200
+
+// Webhook posts a JSON payload to a URL, failing unless it is accepted.
+type Webhook interface {
+	Post(ctx context.Context, url string, payload interface{}) error
+}
+
+type Service struct {
+	repo          Repository
+	familyService *family.Service
+	clientService *client.Service
+	// sender is nil when mail delivery is not configured.
+	sender  report.Sender
+	webhook Webhook
+}
+
+func NewAlertService(repo Repository, familyService *family.Service, clientService *client.Service, sender report.Sender, webhook Webhook) *Service {
+	return &Service{repo: repo, familyService: familyService, clientService: clientService, sender: sender, webhook: webhook}
+}
+
+// managerRoles may read and manage rules and work the alert inbox.
+var managerRoles = []string{user.RoleAdmin, user.RoleLabManager}
+
+func (s *Service) SaveRule(ctx context.Context, rule *Rule) (*Rule, error) {
+	u, err := user.RequireRole(ctx, managerRoles...)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = 0
+	if err := validateRule(rule); err != nil {
+		return nil, err
+	}
+	rule.CreatedBy = &u.ID
+	rule.CreatedAt = time.Now().UTC()
+	if err := s.repo.SaveRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule replaces the settings of a rule. Deactivating a rule stops it
+// from raising alerts but keeps the ones it already raised.
+func (s *Service) UpdateRule(ctx context.Context, rule *Rule) (*Rule, error) {
+	if _, err := user.RequireRole(ctx, managerRoles...); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetRule(rule.ID); err != nil {
+		return nil, err
+	}
+	if err := validateRule(rule); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateRule(rule); err != nil {
+		return nil, err
+	}
+	return s.repo.GetRule(rule.ID)
+}
+
+func validateRule(rule *Rule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	rule.WebhookURL = strings.TrimSpace(rule.WebhookURL)
+
+	v := &domain.Validator{}
+	v.Required("name", rule.Name)
+	v.MaxLength("name", rule.Name, 255)
+	v.Check(rule.ThresholdPercent > 0 && rule.ThresholdPercent <= maxThresholdPercent,
+		"threshold_percent", fmt.Sprintf("must be greater than 0 and at most %d", maxThresholdPercent))
+	if rule.ProjectID != nil {
+		v.RequiredID("project_id", *rule.ProjectID)
+	}
+	if rule.SpecimenType != "" {
+		v.Check(family.ValidSpecimenType(rule.SpecimenType), "specimen_type", "is not a known specimen type")
+	}
+	if rule.AgeDays != nil {
+		v.Check(*rule.AgeDays > 0, "age_days", "must be greater than zero")
+	}
+	for i, email := range rule.Emails {
+		email = strings.TrimSpace(email)
+		rule.Emails[i] = email
+		addr, err := mail.ParseAddress(email)
+		v.Check(err == nil && addr.Address == email, fmt.Sprintf("emails[%d]", i), "is not a valid email address")
+	}
+	if rule.WebhookURL != "" {
+		u, err := url.Parse(rule.WebhookURL)
+		v.Check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"webhook_url", "must be an http or https URL")
+		v.MaxLength("webhook_url", rule.WebhookURL, 2000)
+	}
+	return v.Err()
+}
+
+func (s *Service) GetRule(ctx context.Context, ID int) (*Rule, error) {
+	if _, err := user.RequireRole(ctx, managerRoles...); err != nil {
+		return nil, err
+	}
+	return s.repo.GetRule(ID)
+}
+
+func (s *Service) GetRules(ctx context.Context, req domain.PageRequest) (*domain.Page[*Rule], error) {
+	if _, err := user.RequireRole(ctx, managerRoles...); err != nil {
+		return nil, err
+	}
+	if err := req.Prepare(); err != nil {
+		return nil, err
+	}
+	rules, total, err := s.repo.GetRules(req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(rules, total, req, func(r *Rule) int { return r.ID }), nil
+}
+
+func (s *Service) GetAlert(ctx context.Context, ID int) (*Alert, error) {
+	if _, err := user.RequireRole(ctx, managerRoles...); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAlert(ID)
+}
+
+// GetAlerts lists the alert inbox, newest first.
+func (s *Service) GetAlerts(ctx context.Context, filter Filter) (*domain.Page[*Alert], error) {
+	if _, err := user.RequireRole(ctx, managerRoles...); err != nil {
+		return nil, err
+	}
+	filter.PageRequest.Normalize()
+	v := &domain.Validator{}
+	filter.PageRequest.Validate(v)
+	v.Check(filter.Status == "" || filter.Status == StatusOpen || filter.Status == StatusAcknowledged || filter.Status == StatusResolved,
+		"status", "must be open, acknowledged or resolved")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	alerts, total, err := s.repo.GetAlerts(filter)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(alerts, total, filter.PageRequest, func(a *Alert) int { return a.ID }), nil
+}
+
+// AcknowledgeAlert marks an open alert as being looked into by the
+// authenticated user.
+func (s *Service) AcknowledgeAlert(ctx context.Context, alertID int) (*Alert, error) {
+	u, err := user.RequireRole(ctx, managerRoles...)
+	if err != nil {
+		return nil, err
+	}
+	a, err := s.repo.GetAlert(alertID)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status != StatusOpen {
+		return nil, fmt.Errorf("alert %d is already %s: %w", a.ID, a.Status, domain.ErrConflict)
+	}
+
+	now := time.Now().UTC()
+	a.Status = StatusAcknowledged
+	a.AcknowledgedBy = &u.ID
+	a.AcknowledgedAt = &now
+	if err := s.repo.UpdateAlertStatus(a, StatusOpen); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ResolveAlert closes an alert, acknowledged or not, with a note on what
+// was done about it.
+func (s *Service) ResolveAlert(ctx context.Context, alertID int, note string) (*Alert, error) {
+	u, err := user.RequireRole(ctx, managerRoles...)
+	if err != nil {
+		return nil, err
+	}
+	a, err := s.repo.GetAlert(alertID)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status == StatusResolved {
+		return nil, fmt.Errorf("alert %d is already resolved: %w", a.ID, domain.ErrConflict)
+	}
+
+	note = strings.TrimSpace(note)
+	v := &domain.Validator{}
+	v.Required("note", note)
+	v.MaxLength("note", note, 1000)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	from := a.Status
+	a.Status = StatusResolved
+	a.ResolvedBy = &u.ID
+	a.ResolvedAt = &now
+	a.ResolutionNote = &note
+	if a.AcknowledgedAt == nil {
+		a.AcknowledgedBy, a.AcknowledgedAt = &u.ID, &now
+	}
+	if err := s.repo.UpdateAlertStatus(a, from); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
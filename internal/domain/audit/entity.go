@@ -0,0 +1,79 @@
+// Package audit keeps the trail of data changes required for lab
+// accreditation (ISO/IEC 17025): who changed what, when, and how.
+package audit
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// Audited entities.
+const (
+	EntityClient    = "client"
+	EntityContact   = "client_contact"
+	EntityProject   = "project"
+	EntityFamily    = "family"
+	EntityMember    = "member"
+	EntityCurve     = "member_curve"
+	EntityEquipment = "equipment"
+	EntityUser      = "user"
+)
+
+// Actions recorded for an entity.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+type Entry struct {
+	ID        int       `db:"id" json:"id"`
+	Entity    string    `db:"entity" json:"entity"`
+	EntityID  int       `db:"entity_id" json:"entity_id"`
+	Action    string    `db:"action" json:"action"`
+	UserID    *int      `db:"user_id" json:"user_id"`
+	Username  *string   `db:"username" json:"username"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	Before    JSON      `db:"before_json" json:"before"`
+	After     JSON      `db:"after_json" json:"after"`
+	// Diff maps every changed field to its {"from", "to"} values.
+	Diff JSON `db:"diff_json" json:"diff"`
+}
+
+// Change is the value of a field before and after an operation.
+type Change struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// JSON is a JSON document stored as text. It is emitted as-is in responses.
+type JSON []byte
+
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+func (j *JSON) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*j = nil
+	case string:
+		*j = JSON(v)
+	case []byte:
+		*j = append(JSON(nil), v...)
+	default:
+		return fmt.Errorf("unsupported type: %T", value)
+	}
+	return nil
+}
+
+func (j JSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return string(j), nil
+}
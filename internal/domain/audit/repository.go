@@ -0,0 +1,8 @@
+package audit
+
+import "github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+
+type Repository interface {
+	SaveEntry(entry *Entry) error
+	GetEntries(filter Filter, req domain.PageRequest) ([]*Entry, int, error)
+}
@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/user"
+)
+
+// Recorder is what the other domain services depend on to leave a trace of
+// their changes. A change that cannot be traced must fail its request.
+type Recorder interface {
+	Record(ctx context.Context, entity string, entityID int, action string, before, after interface{}) error
+}
+
+// Filter narrows the audit trail. Zero values match everything.
+type Filter struct {
+	Entity   string
+	EntityID int
+	UserID   int
+}
+
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+func NewAuditService(repo Repository) *Service {
+	return &Service{repo: repo, now: time.Now}
+}
+
+// Record stores who performed action on the entity, with before/after
+// snapshots and their diff. The change it documents is already committed,
+// so callers return the error to fail the request rather than leave the
+// change untraced silently.
+func (s *Service) Record(ctx context.Context, entity string, entityID int, action string, before, after interface{}) error {
+	entry := &Entry{
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		CreatedAt: s.now().UTC(),
+	}
+	if u, ok := user.FromContext(ctx); ok {
+		entry.UserID = &u.ID
+		entry.Username = &u.Username
+	}
+
+	var err error
+	if entry.Before, err = snapshot(before); err != nil {
+		return fmt.Errorf("audit of %s %d: encoding before: %w", entity, entityID, err)
+	}
+	if entry.After, err = snapshot(after); err != nil {
+		return fmt.Errorf("audit of %s %d: encoding after: %w", entity, entityID, err)
+	}
+	if entry.Diff, err = diff(entry.Before, entry.After); err != nil {
+		return fmt.Errorf("audit of %s %d: diffing: %w", entity, entityID, err)
+	}
+
+	if err := s.repo.SaveEntry(entry); err != nil {
+		log.Printf("[audit.Record] Failed saving entry for %s %d. err=%v", entity, entityID, err)
+		return fmt.Errorf("audit of %s %d: %w", entity, entityID, err)
+	}
+	return nil
+}
+
+// GetEntries lists the audit trail. Only admins and lab managers may read
+// it.
+func (s *Service) GetEntries(ctx context.Context, filter Filter, req domain.PageRequest) (*domain.Page[*Entry], error) {
+	if _, err := user.RequireRole(ctx, user.RoleAdmin, user.RoleLabManager); err != nil {
+		return nil, err
+	}
+
+	v := &domain.Validator{}
+	req.Normalize()
+	req.Validate(v)
+	switch filter.Entity {
+	case "", EntityClient, EntityContact, EntityProject, EntityFamily, EntityMember, EntityCurve, EntityEquipment, EntityUser:
+	default:
+		v.Check(false, "entity", "must be client, client_contact, project, family, member, member_curve, equipment or user")
+	}
+	v.Check(filter.EntityID == 0 || filter.Entity != "", "id", "requires entity")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, total, err := s.repo.GetEntries(filter, req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(entries, total, req, func(e *Entry) int { return e.ID }), nil
+}
+
+func snapshot(value interface{}) (JSON, error) {
+	if value == nil || reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil() {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
+
+// diff compares the top-level fields of two JSON objects.
+func diff(before, after JSON) (JSON, error) {
+	from, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	// A field missing on one side counts as null, so creating an entity does
+	// not list every empty field as changed.
+	changes := map[string]Change{}
+	for k, v := range from {
+		if !reflect.DeepEqual(v, to[k]) {
+			changes[k] = Change{From: v, To: to[k]}
+		}
+	}
+	for k, w := range to {
+		if _, ok := from[k]; !ok && w != nil {
+			changes[k] = Change{To: w}
+		}
+	}
+	return json.Marshal(changes)
+}
+
+func fields(doc JSON) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	if len(doc) == 0 {
+		return out, nil
+	}
+	if err := json.Unmarshal(doc, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/audit"
+)
+
+func (s *Service) GetContacts(clientID int, req domain.PageRequest) (*domain.Page[*Contact], error) {
+	if err := req.Prepare(); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetClient(clientID); err != nil {
+		return nil, err
+	}
+	contacts, total, err := s.repo.GetContacts(clientID, req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(contacts, total, req, func(c *Contact) int { return c.ID }), nil
+}
+
+func (s *Service) GetContact(clientID, contactID int) (*Contact, error) {
+	return s.repo.GetContact(clientID, contactID)
+}
+
+func (s *Service) SaveContact(ctx context.Context, clientID int, contact *Contact) (*Contact, error) {
+	if _, err := s.repo.GetClient(clientID); err != nil {
+		return nil, err
+	}
+	contact.ID = 0
+	contact.ClientID = clientID
+	if err := validateContact(contact); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveContact(contact); err != nil {
+		return nil, err
+	}
+	if err := s.audit.Record(ctx, audit.EntityContact, contact.ID, audit.ActionCreate, nil, contact); err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+func (s *Service) UpdateContact(ctx context.Context, clientID int, contact *Contact) (*Contact, error) {
+	before, err := s.repo.GetContact(clientID, contact.ID)
+	if err != nil {
+		return nil, err
+	}
+	contact.ClientID = clientID
+	if err := validateContact(contact); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateContact(contact); err != nil {
+		return nil, err
+	}
+	if err := s.audit.Record(ctx, audit.EntityContact, contact.ID, audit.ActionUpdate, before, contact); err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+func (s *Service) DeleteContact(ctx context.Context, clientID, contactID int) error {
+	before, err := s.repo.GetContact(clientID, contactID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteContact(clientID, contactID); err != nil {
+		return err
+	}
+	if err := s.audit.Record(ctx, audit.EntityContact, contactID, audit.ActionDelete, before, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReportRecipients resolves who a report of the client goes to. Without
+// explicit contactIDs the contacts flagged to receive reports are used;
+// otherwise every id must be a contact of the client with an email.
+func (s *Service) ReportRecipients(clientID int, contactIDs []int) ([]Contact, error) {
+	contacts, err := s.repo.GetAllContacts(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(contactIDs) == 0 {
+		recipients := []Contact{}
+		for _, c := range contacts {
+			if c.ReceivesReports && c.Email != "" {
+				recipients = append(recipients, c)
+			}
+		}
+		return recipients, nil
+	}
+
+	byID := make(map[int]Contact, len(contacts))
+	for _, c := range contacts {
+		byID[c.ID] = c
+	}
+
+	v := &domain.Validator{}
+	recipients := make([]Contact, 0, len(contactIDs))
+	seen := make(map[int]bool, len(contactIDs))
+	for i, id := range contactIDs {
+		field := fmt.Sprintf("recipients[%d]", i)
+		c, ok := byID[id]
+		v.Check(ok, field, "is not a contact of the client")
+		if !ok || seen[id] {
+			continue
+		}
+		v.Check(c.Email != "", field, "contact has no email")
+		seen[id] = true
+		recipients = append(recipients, c)
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
+
+func validateContact(c *Contact) error {
+	c.Email = strings.TrimSpace(c.Email)
+
+	v := &domain.Validator{}
+	v.Required("name", c.Name)
+	v.MaxLength("name", c.Name, 255)
+	v.MaxLength("role", c.Role, 100)
+	v.MaxLength("phone", c.Phone, 50)
+	v.MaxLength("email", c.Email, 255)
+	if c.Email != "" {
+		addr, err := mail.ParseAddress(c.Email)
+		v.Check(err == nil && addr.Address == c.Email, "email", "is not a valid email address")
+	}
+	v.Check(!c.ReceivesReports || c.Email != "", "email", "is required to receive reports")
+	return v.Err()
+}
@@ -3,4 +3,21 @@ package client
 type Client struct {
 	ID   int    `db:"id" json:"id"`
 	Name string `db:"name" json:"name"`
+	// TaxID is the NIT, optionally with its check digit ("900123456-7").
+	TaxID    string    `db:"tax_id" json:"tax_id"`
+	Address  string    `db:"address" json:"address"`
+	City     string    `db:"city" json:"city"`
+	Contacts []Contact `db:"-" json:"contacts,omitempty"`
+}
+
+// Contact is a person at the client side. Contacts flagged with
+// ReceivesReports are the default recipients of the client's reports.
+type Contact struct {
+	ID              int    `db:"id" json:"id"`
+	ClientID        int    `db:"client_id" json:"client_id"`
+	Name            string `db:"name" json:"name"`
+	Role            string `db:"role" json:"role"`
+	Email           string `db:"email" json:"email"`
+	Phone           string `db:"phone" json:"phone"`
+	ReceivesReports bool   `db:"receives_reports" json:"receives_reports"`
 }
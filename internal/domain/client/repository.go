@@ -1,7 +1,21 @@
 package client
 
+import "github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+
 type Repository interface {
 	SaveClient(client *Client) (*Client, error)
+	UpdateClient(client *Client) error
+	// GetClient wraps domain.ErrNotFound when the client does not exist.
 	GetClient(ID int) (*Client, error)
-	GetAllClients() ([]*Client, error)
+	GetAllClients(req domain.PageRequest) ([]*Client, int, error)
+
+	SaveContact(contact *Contact) error
+	UpdateContact(contact *Contact) error
+	DeleteContact(clientID, contactID int) error
+	// GetContact wraps domain.ErrNotFound when the contact does not exist
+	// or belongs to another client.
+	GetContact(clientID, contactID int) (*Contact, error)
+	GetContacts(clientID int, req domain.PageRequest) ([]*Contact, int, error)
+	// GetAllContacts lists every contact of the client, unpaginated.
+	GetAllContacts(clientID int) ([]Contact, error)
 }
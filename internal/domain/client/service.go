@@ -1,21 +1,79 @@
 package client
 
+import (
+	"context"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/audit"
+)
+
 type Service struct {
-	repo Repository
+	repo  Repository
+	audit audit.Recorder
+}
+
+func NewClientService(repo Repository, recorder audit.Recorder) *Service {
+	return &Service{repo: repo, audit: recorder}
 }
 
-func NewClientService(repo Repository) *Service {
-	return &Service{repo: repo}
+func (s *Service) SaveClient(ctx context.Context, client *Client) (*Client, error) {
+	if err := s.validate(client); err != nil {
+		return nil, err
+	}
+	created, err := s.repo.SaveClient(client)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.Record(ctx, audit.EntityClient, created.ID, audit.ActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+	return created, nil
 }
 
-func (s *Service) SaveClient(client *Client) (*Client, error) {
-	return s.repo.SaveClient(client)
+// UpdateClient replaces the data of an existing client. Contacts are
+// managed through their own endpoints and are left untouched.
+func (s *Service) UpdateClient(ctx context.Context, client *Client) (*Client, error) {
+	before, err := s.repo.GetClient(client.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(client); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateClient(client); err != nil {
+		return nil, err
+	}
+	client.Contacts = before.Contacts
+	if err := s.audit.Record(ctx, audit.EntityClient, client.ID, audit.ActionUpdate, before, client); err != nil {
+		return nil, err
+	}
+	return client, nil
 }
 
 func (s *Service) GetClient(ID int) (*Client, error) {
 	return s.repo.GetClient(ID)
 }
 
-func (s *Service) GetAllClients() ([]*Client, error) {
-	return s.repo.GetAllClients()
+func (s *Service) GetAllClients(req domain.PageRequest) (*domain.Page[*Client], error) {
+	if err := req.Prepare(); err != nil {
+		return nil, err
+	}
+	clients, total, err := s.repo.GetAllClients(req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(clients, total, req, func(c *Client) int { return c.ID }), nil
+}
+
+func (s *Service) validate(client *Client) error {
+	v := &domain.Validator{}
+	v.Required("name", client.Name)
+	v.MaxLength("name", client.Name, 255)
+	client.TaxID = NormalizeTaxID(client.TaxID)
+	if client.TaxID != "" {
+		v.Check(ValidTaxID(client.TaxID), "tax_id", "is not a valid NIT")
+	}
+	v.MaxLength("address", client.Address, 255)
+	v.MaxLength("city", client.City, 100)
+	return v.Err()
 }
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"regexp"
+	"strings"
+)
+
+var taxIDPattern = regexp.MustCompile(`^[0-9]{5,15}(-[0-9])?$`)
+
+// nitWeights are the DIAN prime weights, applied from the rightmost digit.
+var nitWeights = []int{3, 7, 13, 17, 19, 23, 29, 37, 41, 43, 47, 53, 59, 67, 71}
+
+// NormalizeTaxID drops the dots and spaces people usually type in a NIT.
+func NormalizeTaxID(taxID string) string {
+	return strings.NewReplacer(".", "", " ", "").Replace(strings.TrimSpace(taxID))
+}
+
+// ValidTaxID reports whether a normalized NIT is well formed and, when it
+// carries a check digit, whether the digit matches.
+func ValidTaxID(taxID string) bool {
+	if !taxIDPattern.MatchString(taxID) {
+		return false
+	}
+	number, dv, hasDV := strings.Cut(taxID, "-")
+	if !hasDV {
+		return true
+	}
+	return checkDigit(number) == int(dv[0]-'0')
+}
+
+func checkDigit(number string) int {
+	sum := 0
+	for i := 0; i < len(number); i++ {
+		digit := int(number[len(number)-1-i] - '0')
+		sum += digit * nitWeights[i]
+	}
+	r := sum % 11
+	if r > 1 {
+		return 11 - r
+	}
+	return r
+}
@@ -0,0 +1,63 @@
+package equipment
+
+import (
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+)
+
+// Equipment kinds. Fracture results are linked to machines; load cells are
+// mounted on a machine and must be calibrated for it to be usable.
+const (
+	KindMachine  = "machine"
+	KindLoadCell = "load_cell"
+)
+
+type Equipment struct {
+	ID           int      `db:"id" json:"id"`
+	Kind         string   `db:"kind" json:"kind"`
+	Name         string   `db:"name" json:"name"`
+	Manufacturer string   `db:"manufacturer" json:"manufacturer"`
+	Model        string   `db:"model" json:"model"`
+	SerialNumber string   `db:"serial_number" json:"serial_number"`
+	CapacityKN   *float64 `db:"capacity_kn" json:"capacity_kn"`
+	// MachineID is the machine a load cell is mounted on.
+	MachineID *int `db:"machine_id" json:"machine_id"`
+	// Retired equipment can no longer be linked to new results.
+	Retired bool `db:"retired" json:"retired"`
+	// CalibratedUntil is the latest validity date among its certificates,
+	// empty when it has none.
+	CalibratedUntil domain.Date   `db:"calibrated_until" json:"calibrated_until"`
+	Calibrations    []Calibration `db:"-" json:"calibrations,omitempty"`
+}
+
+// Calibration is a calibration certificate. It is valid from CalibratedOn
+// to ValidUntil, both inclusive.
+type Calibration struct {
+	ID                int         `db:"id" json:"id"`
+	EquipmentID       int         `db:"equipment_id" json:"equipment_id"`
+	CertificateNumber string      `db:"certificate_number" json:"certificate_number"`
+	Laboratory        string      `db:"laboratory" json:"laboratory"`
+	CalibratedOn      domain.Date `db:"calibrated_on" json:"calibrated_on"`
+	ValidUntil        domain.Date `db:"valid_until" json:"valid_until"`
+	Notes             string      `db:"notes" json:"notes"`
+}
+
+// ListFilter holds the criteria used to list equipment.
+type ListFilter struct {
+	Kind string
+	// DueBefore keeps equipment whose calibration lapses before that day,
+	// or that has never been calibrated.
+	DueBefore *time.Time
+	// IncludeRetired also lists retired equipment.
+	IncludeRetired bool
+	domain.PageRequest
+}
+
+// TestedFilter selects the members broken on a machine in a period.
+type TestedFilter struct {
+	MachineID int
+	From      *time.Time
+	To        *time.Time
+	domain.PageRequest
+}
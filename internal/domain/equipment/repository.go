@@ -0,0 +1,21 @@
+package equipment
+
+import (
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
+)
+
+type Repository interface {
+	SaveEquipment(e *Equipment) error
+	UpdateEquipment(e *Equipment) error
+	// GetEquipment loads the equipment with its certificates. It wraps
+	// domain.ErrNotFound when it does not exist.
+	GetEquipment(ID int) (*Equipment, error)
+	GetEquipmentList(filter ListFilter) ([]*Equipment, int, error)
+
+	SaveCalibration(c *Calibration) error
+	GetCalibrations(equipmentID int, req domain.PageRequest) ([]*Calibration, int, error)
+
+	// GetTestedMembers lists the members broken on a machine, newest first.
+	GetTestedMembers(filter TestedFilter) ([]*member.Member, int, error)
+}
@@ -0,0 +1,175 @@
+package equipment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/audit"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
+)
+
+type Service struct {
+	repo  Repository
+	audit audit.Recorder
+}
+
+func NewEquipmentService(repo Repository, recorder audit.Recorder) *Service {
+	return &Service{repo: repo, audit: recorder}
+}
+
+func (s *Service) SaveEquipment(ctx context.Context, e *Equipment) (*Equipment, error) {
+	e.ID = 0
+	if err := s.validate(e); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveEquipment(e); err != nil {
+		return nil, err
+	}
+	if err := s.audit.Record(ctx, audit.EntityEquipment, e.ID, audit.ActionCreate, nil, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// UpdateEquipment replaces the data of existing equipment. Certificates are
+// added through their own endpoint and are left untouched.
+func (s *Service) UpdateEquipment(ctx context.Context, e *Equipment) (*Equipment, error) {
+	before, err := s.repo.GetEquipment(e.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(e); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateEquipment(e); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.GetEquipment(e.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.Record(ctx, audit.EntityEquipment, e.ID, audit.ActionUpdate, before, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *Service) validate(e *Equipment) error {
+	v := &domain.Validator{}
+	v.Check(e.Kind == KindMachine || e.Kind == KindLoadCell, "kind", "must be machine or load_cell")
+	v.Required("name", e.Name)
+	v.MaxLength("name", e.Name, 255)
+	v.MaxLength("manufacturer", e.Manufacturer, 255)
+	v.MaxLength("model", e.Model, 255)
+	v.MaxLength("serial_number", e.SerialNumber, 100)
+	if e.CapacityKN != nil {
+		v.Positive("capacity_kn", *e.CapacityKN)
+	}
+
+	if e.MachineID != nil {
+		v.Check(e.Kind == KindLoadCell, "machine_id", "only load cells are mounted on a machine")
+		v.Check(*e.MachineID != e.ID, "machine_id", "must be another equipment")
+		if !v.HasErrors("machine_id") {
+			machine, err := s.repo.GetEquipment(*e.MachineID)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				return err
+			}
+			v.Check(machine != nil && machine.Kind == KindMachine, "machine_id", "machine does not exist")
+		}
+	}
+
+	return v.Err()
+}
+
+func (s *Service) GetEquipment(ID int) (*Equipment, error) {
+	return s.repo.GetEquipment(ID)
+}
+
+func (s *Service) GetEquipmentList(filter ListFilter) (*domain.Page[*Equipment], error) {
+	filter.PageRequest.Normalize()
+	v := &domain.Validator{}
+	filter.PageRequest.Validate(v)
+	v.Check(filter.Kind == "" || filter.Kind == KindMachine || filter.Kind == KindLoadCell,
+		"kind", "must be machine or load_cell")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	list, total, err := s.repo.GetEquipmentList(filter)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(list, total, filter.PageRequest, func(e *Equipment) int { return e.ID }), nil
+}
+
+func (s *Service) SaveCalibration(ctx context.Context, equipmentID int, c *Calibration) (*Calibration, error) {
+	before, err := s.repo.GetEquipment(equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	c.ID = 0
+	c.EquipmentID = equipmentID
+
+	v := &domain.Validator{}
+	v.Required("certificate_number", c.CertificateNumber)
+	v.MaxLength("certificate_number", c.CertificateNumber, 100)
+	v.MaxLength("laboratory", c.Laboratory, 255)
+	v.RequiredDate("calibrated_on", c.CalibratedOn.Time)
+	v.RequiredDate("valid_until", c.ValidUntil.Time)
+	if !c.CalibratedOn.IsZero() && !c.ValidUntil.IsZero() {
+		v.Check(c.ValidUntil.After(c.CalibratedOn.Time), "valid_until", "must be after calibrated_on")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveCalibration(c); err != nil {
+		return nil, err
+	}
+	after, err := s.repo.GetEquipment(equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.Record(ctx, audit.EntityEquipment, equipmentID, audit.ActionUpdate, before, after); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *Service) GetCalibrations(equipmentID int, req domain.PageRequest) (*domain.Page[*Calibration], error) {
+	if err := req.Prepare(); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetEquipment(equipmentID); err != nil {
+		return nil, err
+	}
+	calibrations, total, err := s.repo.GetCalibrations(equipmentID, req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(calibrations, total, req, func(c *Calibration) int { return c.ID }), nil
+}
+
+// GetTestedMembers lists the members broken on a machine, optionally in a
+// period, so the results affected by a lapsed calibration can be found.
+func (s *Service) GetTestedMembers(filter TestedFilter) (*domain.Page[*member.Member], error) {
+	filter.PageRequest.Normalize()
+	v := &domain.Validator{}
+	filter.PageRequest.Validate(v)
+	if filter.From != nil && filter.To != nil {
+		v.Check(!filter.To.Before(*filter.From), "to", "must not be before from")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetEquipment(filter.MachineID); err != nil {
+		return nil, err
+	}
+	members, total, err := s.repo.GetTestedMembers(filter)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(members, total, filter.PageRequest, func(m *member.Member) int { return m.ID }), nil
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "errors"
+
+// ErrNotFound is wrapped by repositories and services when the requested
+// entity does not exist (or does not belong to the given parent).
+var ErrNotFound = errors.New("not found")
+
+// ErrUnauthorized is returned when an operation needs an authenticated user
+// and the request carries none.
+var ErrUnauthorized = errors.New("authentication required")
+
+// ErrForbidden is returned when the authenticated user lacks the role the
+// operation requires.
+var ErrForbidden = errors.New("forbidden")
+
+// ErrConflict is returned when the operation clashes with the current state
+// of the entity.
+var ErrConflict = errors.New("conflict")
+
+// ErrUnavailable is returned when the operation needs an external service
+// that is not configured or cannot be reached.
+var ErrUnavailable = errors.New("service unavailable")
@@ -7,8 +7,16 @@ import (
 )
 
 type Family struct {
-	ID               int             `db:"id" json:"id"`
-	FamilyType       string          `db:"type" json:"family_type"`
+	ID int `db:"id" json:"id"`
+	// FamilyType is a free label; SpecimenType drives the strength formula.
+	FamilyType   string `db:"type" json:"family_type"`
+	SpecimenType string `db:"specimen_type" json:"specimen_type"`
+	// Side is used by cubes; Width, Depth and Span by beams. Cylinders and
+	// cores use Radius and Height. All in cm.
+	Side             float64         `db:"side" json:"side"`
+	Width            float64         `db:"width" json:"width"`
+	Depth            float64         `db:"depth" json:"depth"`
+	Span             float64         `db:"span" json:"span"`
 	SamplePlace      string          `db:"sample_place" json:"sample_place"`
 	DateOfEntry      time.Time       `db:"date_of_entry" json:"date_of_entry"`
 	Radius           float64         `db:"radius" json:"radius"`
@@ -18,4 +26,17 @@ type Family struct {
 	ProjectID        int             `db:"project_id" json:"project_id"`
 	Members          []member.Member `db:"-" json:"members"`
 	DesignResistance float64         `db:"design_resistance" json:"design_resistance"`
+	Stats            *Stats          `db:"-" json:"stats,omitempty"`
+
+	// Field sampling data. Measurements are optional because older
+	// families were registered without them.
+	SlumpCM              *float64 `db:"slump_cm" json:"slump_cm"`
+	ConcreteTemperatureC *float64 `db:"concrete_temperature_c" json:"concrete_temperature_c"`
+	AmbientTemperatureC  *float64 `db:"ambient_temperature_c" json:"ambient_temperature_c"`
+	Supplier             string   `db:"supplier" json:"supplier"`
+	// TruckTicket is the remission number of the mixer truck sampled.
+	TruckTicket        string `db:"truck_ticket" json:"truck_ticket"`
+	MixDesignCode      string `db:"mix_design_code" json:"mix_design_code"`
+	StructuralElement  string `db:"structural_element" json:"structural_element"`
+	SamplingTechnician string `db:"sampling_technician" json:"sampling_technician"`
 }
@@ -1,5 +1,18 @@
 package family
 
+import "github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+
 type Repository interface {
 	SaveFamily(family *Family) (*Family, error)
+	// GetFamilies lists families without their members. A zero projectID
+	// lists the families of every project.
+	GetFamilies(projectID int, req domain.PageRequest) ([]*Family, int, error)
+	// GetFamilyByID returns the family with its members and their operatives,
+	// or an error wrapping domain.ErrNotFound.
+	GetFamilyByID(ID int) (*Family, error)
+	// LoadMembers attaches members and their operatives to the families.
+	LoadMembers(families []*Family) error
+	// GetProjectClientID returns the client that owns the project and whether
+	// the project exists at all.
+	GetProjectClientID(projectID int) (int, bool, error)
 }
@@ -1,13 +1,173 @@
 package family
 
+import (
+	"context"
+	"fmt"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/audit"
+)
+
 type Service struct {
-	repo Repository
+	repo  Repository
+	audit audit.Recorder
+}
+
+func NewFamilyService(repo Repository, recorder audit.Recorder) *Service {
+	return &Service{repo: repo, audit: recorder}
+}
+
+func (s *Service) SaveFamily(ctx context.Context, family Family) (*Family, error) {
+	if err := s.validate(&family); err != nil {
+		return nil, err
+	}
+	created, err := s.repo.SaveFamily(&family)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.Record(ctx, audit.EntityFamily, created.ID, audit.ActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *Service) validate(family *Family) error {
+	if family.SpecimenType == "" {
+		family.SpecimenType = SpecimenCylinder
+	}
+	if family.FamilyType == "" {
+		family.FamilyType = family.SpecimenType
+	}
+
+	v := &domain.Validator{}
+	v.Required("sample_place", family.SamplePlace)
+	v.RequiredDate("date_of_entry", family.DateOfEntry)
+	validateGeometry(v, family)
+	v.NonNegative("classification", family.Classification)
+	v.Positive("design_resistance", family.DesignResistance)
+	v.RequiredID("project_id", family.ProjectID)
+	v.RequiredID("client_id", family.ClientID)
+	validateSampling(v, family)
+
+	if !v.HasErrors("project_id") {
+		clientID, exists, err := s.repo.GetProjectClientID(family.ProjectID)
+		if err != nil {
+			return err
+		}
+		v.Check(exists, "project_id", "project does not exist")
+		if exists && !v.HasErrors("client_id") {
+			v.Check(clientID == family.ClientID, "client_id", "does not match the project's client")
+		}
+	}
+
+	return v.Err()
 }
 
-func NewFamilyService(repo Repository) *Service {
-	return &Service{repo: repo}
+// validateGeometry checks the dimensions the specimen type needs. Cores
+// shorter than their diameter cannot be corrected (ASTM C42).
+func validateGeometry(v *domain.Validator, family *Family) {
+	if !ValidSpecimenType(family.SpecimenType) {
+		v.Check(false, "specimen_type", "must be cylinder, cube, beam or core")
+		return
+	}
+	switch family.SpecimenType {
+	case SpecimenCube:
+		v.Positive("side", family.Side)
+	case SpecimenBeam:
+		v.Positive("width", family.Width)
+		v.Positive("depth", family.Depth)
+		v.Positive("span", family.Span)
+	default:
+		v.Positive("radius", family.Radius)
+		v.Positive("height", family.Height)
+		if family.SpecimenType == SpecimenCore && family.Radius > 0 {
+			v.Check(family.Geometry().LengthDiameterRatio() >= 1, "height", "core length must be at least its diameter")
+		}
+	}
 }
 
-func (s *Service) SaveFamily(family Family) (*Family, error) {
-	return s.repo.SaveFamily(&family)
+// Plausible ranges for field measurements; anything outside is a typo.
+const (
+	maxSlumpCM        = 30
+	minConcreteTempC  = 0
+	maxConcreteTempC  = 50
+	minAmbientTempC   = -20
+	maxAmbientTempC   = 60
+	maxSamplingLength = 100
+)
+
+func validateSampling(v *domain.Validator, family *Family) {
+	if family.SlumpCM != nil {
+		v.Check(*family.SlumpCM >= 0 && *family.SlumpCM <= maxSlumpCM, "slump_cm", "must be between 0 and 30")
+	}
+	if family.ConcreteTemperatureC != nil {
+		v.Check(*family.ConcreteTemperatureC >= minConcreteTempC && *family.ConcreteTemperatureC <= maxConcreteTempC,
+			"concrete_temperature_c", "must be between 0 and 50")
+	}
+	if family.AmbientTemperatureC != nil {
+		v.Check(*family.AmbientTemperatureC >= minAmbientTempC && *family.AmbientTemperatureC <= maxAmbientTempC,
+			"ambient_temperature_c", "must be between -20 and 60")
+	}
+	v.MaxLength("supplier", family.Supplier, 255)
+	v.MaxLength("truck_ticket", family.TruckTicket, maxSamplingLength)
+	v.MaxLength("mix_design_code", family.MixDesignCode, maxSamplingLength)
+	v.MaxLength("structural_element", family.StructuralElement, 255)
+	v.MaxLength("sampling_technician", family.SamplingTechnician, 255)
+}
+
+func (s *Service) GetFamilies(projectID int, req domain.PageRequest) (*domain.Page[*Family], error) {
+	if err := req.Prepare(); err != nil {
+		return nil, err
+	}
+	families, total, err := s.repo.GetFamilies(projectID, req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(families, total, req, func(f *Family) int { return f.ID }), nil
+}
+
+func (s *Service) GetFamilyByID(ID int) (*Family, error) {
+	family, err := s.repo.GetFamilyByID(ID)
+	if err != nil {
+		return nil, err
+	}
+	family.ComputeStats()
+	return family, nil
+}
+
+// GetProjectFamily returns the family only if it belongs to the project.
+func (s *Service) GetProjectFamily(projectID int, familyID int) (*Family, error) {
+	family, err := s.GetFamilyByID(familyID)
+	if err != nil {
+		return nil, err
+	}
+	if family.ProjectID != projectID {
+		return nil, fmt.Errorf("family %d in project %d: %w", familyID, projectID, domain.ErrNotFound)
+	}
+	return family, nil
+}
+
+// GetProjectFamilies lists the families of a project with their members and
+// stats.
+func (s *Service) GetProjectFamilies(projectID int, req domain.PageRequest) (*domain.Page[*Family], error) {
+	if err := req.Prepare(); err != nil {
+		return nil, err
+	}
+	if _, exists, err := s.repo.GetProjectClientID(projectID); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, fmt.Errorf("project %d: %w", projectID, domain.ErrNotFound)
+	}
+
+	families, total, err := s.repo.GetFamilies(projectID, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.LoadMembers(families); err != nil {
+		return nil, err
+	}
+	for _, f := range families {
+		f.ComputeStats()
+	}
+	return domain.NewPage(families, total, req, func(f *Family) int { return f.ID }), nil
 }
@@ -0,0 +1,126 @@
+package family
+
+import "math"
+
+// Specimen types. Cylinders, cubes and drilled cores are broken in
+// compression; beams (prisms) are broken in flexure and give a modulus of
+// rupture instead.
+const (
+	SpecimenCylinder = "cylinder"
+	SpecimenCube     = "cube"
+	SpecimenBeam     = "beam"
+	SpecimenCore     = "core"
+)
+
+func ValidSpecimenType(specimenType string) bool {
+	switch specimenType {
+	case SpecimenCylinder, SpecimenCube, SpecimenBeam, SpecimenCore:
+		return true
+	}
+	return false
+}
+
+// Geometry holds the dimensions, in cm, a specimen's strength depends on.
+// Only the fields of its SpecimenType are used.
+type Geometry struct {
+	SpecimenType string
+	// Radius and Height describe cylinders and cores.
+	Radius float64
+	Height float64
+	// Side is the edge of a cube.
+	Side float64
+	// Width and Depth are the beam cross-section and Span the distance
+	// between supports.
+	Width float64
+	Depth float64
+	Span  float64
+}
+
+// Flexural reports whether the specimen is tested in flexure.
+func (g Geometry) Flexural() bool {
+	return g.SpecimenType == SpecimenBeam
+}
+
+// Valid reports whether every dimension the specimen type needs is set.
+func (g Geometry) Valid() bool {
+	switch g.SpecimenType {
+	case SpecimenCube:
+		return g.Side > 0
+	case SpecimenBeam:
+		return g.Width > 0 && g.Depth > 0 && g.Span > 0
+	default:
+		return g.Radius > 0 && g.Height > 0
+	}
+}
+
+// Area is the loaded cross-section in cm². Beams have none.
+func (g Geometry) Area() float64 {
+	switch g.SpecimenType {
+	case SpecimenCube:
+		return g.Side * g.Side
+	case SpecimenBeam:
+		return 0
+	default:
+		return math.Pi * math.Pow(g.Radius, 2)
+	}
+}
+
+// Volume is the specimen volume in cm³. It is 0 for beams, whose length is
+// not recorded.
+func (g Geometry) Volume() float64 {
+	switch g.SpecimenType {
+	case SpecimenCube:
+		return math.Pow(g.Side, 3)
+	case SpecimenBeam:
+		return 0
+	default:
+		return g.Area() * g.Height
+	}
+}
+
+// LengthDiameterRatio is the slenderness of cylinders and cores.
+func (g Geometry) LengthDiameterRatio() float64 {
+	if g.Radius <= 0 {
+		return 0
+	}
+	return g.Height / (2 * g.Radius)
+}
+
+// coreCorrection is the ASTM C42 strength correction factor by L/D ratio.
+// Values in between are interpolated linearly.
+var coreCorrection = []struct{ ratio, factor float64 }{
+	{1.00, 0.87},
+	{1.25, 0.93},
+	{1.50, 0.96},
+	{1.75, 0.98},
+	{2.00, 1.00},
+}
+
+// CorrectionFactor adjusts the strength of cores shorter than twice their
+// diameter. It is 1 for every other specimen.
+func (g Geometry) CorrectionFactor() float64 {
+	if g.SpecimenType != SpecimenCore {
+		return 1
+	}
+	ratio := g.LengthDiameterRatio()
+	if ratio <= coreCorrection[0].ratio {
+		return coreCorrection[0].factor
+	}
+	for i := 1; i < len(coreCorrection); i++ {
+		lo, hi := coreCorrection[i-1], coreCorrection[i]
+		if ratio <= hi.ratio {
+			return lo.factor + (ratio-lo.ratio)/(hi.ratio-lo.ratio)*(hi.factor-lo.factor)
+		}
+	}
+	return 1
+}
+
+// StrengthKGCM2 converts a fracture load in kN into kg/cm²: compressive
+// strength, or modulus of rupture under third-point loading for beams.
+func (g Geometry) StrengthKGCM2(load float64) float64 {
+	kgf := load * kNToKgf
+	if g.Flexural() {
+		return kgf * g.Span / (g.Width * g.Depth * g.Depth)
+	}
+	return kgf / g.Area() * g.CorrectionFactor()
+}
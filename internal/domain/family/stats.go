@@ -0,0 +1,84 @@
+package family
+
+import "math"
+
+// Stats summarizes the results of a family's members.
+type Stats struct {
+	MemberCount    int `json:"member_count"`
+	PendingCount   int `json:"pending_count"`
+	FracturedCount int `json:"fractured_count"`
+	ReportedCount  int `json:"reported_count"`
+	// Strength figures are in PSI, like DesignResistance, and only consider
+	// fractured members. For beams they are moduli of rupture. They are nil
+	// while nothing has been fractured.
+	AverageStrengthPSI *float64 `json:"average_strength_psi"`
+	MinStrengthPSI     *float64 `json:"min_strength_psi"`
+	MaxStrengthPSI     *float64 `json:"max_strength_psi"`
+	// AcceptanceCount is the number of members broken at AcceptanceAgeDays or
+	// later, CompliantCount those of them that reached the design resistance,
+	// and Compliance the percentage.
+	AcceptanceCount int      `json:"acceptance_count"`
+	CompliantCount  int      `json:"compliant_count"`
+	Compliance      *float64 `json:"compliance"`
+	// AverageDensity is in kg/m³, over the members whose mass was recorded.
+	AverageDensity *float64 `json:"average_density,omitempty"`
+}
+
+// ComputeStats fills f.Stats from the members currently loaded.
+func (f *Family) ComputeStats() {
+	stats := &Stats{MemberCount: len(f.Members)}
+
+	var sum, densitySum float64
+	min, max := math.Inf(1), math.Inf(-1)
+	var measured, weighed int
+
+	for i, m := range f.Members {
+		if density := f.MemberDensity(m); density != nil {
+			f.Members[i].Density = density
+			densitySum += *density
+			weighed++
+		}
+		if m.IsReported != nil && *m.IsReported {
+			stats.ReportedCount++
+		}
+		if m.Result == nil {
+			stats.PendingCount++
+			continue
+		}
+		stats.FracturedCount++
+		if !f.MemberGeometry(m).Valid() {
+			continue
+		}
+
+		strength := f.MemberStrengthPSI(m)
+		measured++
+		sum += strength
+		min = math.Min(min, strength)
+		max = math.Max(max, strength)
+
+		if m.FractureDays != nil && *m.FractureDays >= AcceptanceAgeDays {
+			stats.AcceptanceCount++
+			if strength >= f.DesignResistance {
+				stats.CompliantCount++
+			}
+		}
+	}
+
+	if measured > 0 {
+		avg := sum / float64(measured)
+		stats.AverageStrengthPSI = &avg
+		stats.MinStrengthPSI = &min
+		stats.MaxStrengthPSI = &max
+	}
+	if stats.AcceptanceCount > 0 {
+		compliance := float64(stats.CompliantCount) / float64(stats.AcceptanceCount) * 100
+		stats.Compliance = &compliance
+	}
+
+	if weighed > 0 {
+		density := densitySum / float64(weighed)
+		stats.AverageDensity = &density
+	}
+
+	f.Stats = stats
+}
@@ -0,0 +1,81 @@
+package family
+
+import "github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
+
+// AcceptanceAgeDays is the age at which specimens are checked against the
+// design resistance. Earlier breaks are not expected to reach it.
+const AcceptanceAgeDays = 28
+
+const (
+	// kNToKgf converts a load in kilonewtons to kilograms-force.
+	kNToKgf = 102
+	// kgCM2ToPSI converts kg/cm² to PSI.
+	kgCM2ToPSI = 1 / 0.07
+	// psiToMPa converts PSI to MPa.
+	psiToMPa = 1 / 145.0377
+	// cm3PerM3 converts volumes in cm³ to m³.
+	cm3PerM3 = 1e6
+)
+
+// Geometry returns the nominal geometry of the family's specimens.
+func (f Family) Geometry() Geometry {
+	return Geometry{
+		SpecimenType: f.SpecimenType,
+		Radius:       f.Radius,
+		Height:       f.Height,
+		Side:         f.Side,
+		Width:        f.Width,
+		Depth:        f.Depth,
+		Span:         f.Span,
+	}
+}
+
+// MemberGeometry returns the geometry of one specimen: its own measured
+// diameter and height when recorded, the family nominal otherwise.
+func (f Family) MemberGeometry(m member.Member) Geometry {
+	g := f.Geometry()
+	if g.SpecimenType != SpecimenCylinder && g.SpecimenType != SpecimenCore && g.SpecimenType != "" {
+		return g
+	}
+	if d, ok := m.MeasuredDiameter(); ok {
+		g.Radius = d / 2
+	}
+	if m.HeightCM != nil {
+		g.Height = *m.HeightCM
+	}
+	return g
+}
+
+// MemberStrengthPSI is the strength of one fractured member, using its
+// measured dimensions.
+func (f Family) MemberStrengthPSI(m member.Member) float64 {
+	return f.MemberGeometry(m).StrengthKGCM2(*m.Result) * kgCM2ToPSI
+}
+
+// MemberDensity returns the density of the member in kg/m³, or nil when its
+// mass or volume is unknown.
+func (f Family) MemberDensity(m member.Member) *float64 {
+	if m.MassKG == nil {
+		return nil
+	}
+	volume := f.MemberGeometry(m).Volume()
+	if volume <= 0 {
+		return nil
+	}
+	density := *m.MassKG / (volume / cm3PerM3)
+	return &density
+}
+
+// StrengthKGCM2 converts a fracture load in kN into strength for the
+// family's nominal geometry.
+func (f Family) StrengthKGCM2(load float64) float64 {
+	return f.Geometry().StrengthKGCM2(load)
+}
+
+func (f Family) StrengthPSI(load float64) float64 {
+	return f.StrengthKGCM2(load) * kgCM2ToPSI
+}
+
+func (f Family) DesignResistanceMPA() float64 {
+	return f.DesignResistance * psiToMPa
+}
@@ -0,0 +1,182 @@
+package member
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/audit"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/user"
+)
+
+// Correction statuses. A correction starts pending and is reviewed once.
+const (
+	CorrectionPending  = "pending"
+	CorrectionApproved = "approved"
+	CorrectionRejected = "rejected"
+)
+
+// Correction is a requested change to the result of a fractured member.
+// The member keeps its result until a lab manager approves it.
+type Correction struct {
+	ID             int        `db:"id" json:"id"`
+	MemberID       int        `db:"member_id" json:"member_id"`
+	PreviousResult *float64   `db:"previous_result" json:"previous_result"`
+	ProposedResult float64    `db:"proposed_result" json:"proposed_result"`
+	Reason         string     `db:"reason" json:"reason"`
+	Status         string     `db:"status" json:"status"`
+	RequestedBy    int        `db:"requested_by" json:"requested_by"`
+	RequestedAt    time.Time  `db:"requested_at" json:"requested_at"`
+	ReviewedBy     *int       `db:"reviewed_by" json:"reviewed_by"`
+	ReviewedAt     *time.Time `db:"reviewed_at" json:"reviewed_at"`
+	ReviewNote     *string    `db:"review_note" json:"review_note"`
+}
+
+// CorrectionFilter narrows a correction listing. Zero values match all.
+type CorrectionFilter struct {
+	MemberID int
+	Status   string
+}
+
+// RequestCorrection files a correction for the result of memberID on behalf
+// of the authenticated user.
+func (s *Service) RequestCorrection(ctx context.Context, memberID int, c *Correction) (*Correction, error) {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+
+	m, err := s.repo.GetMemberByID(memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &domain.Validator{}
+	v.Check(m.Result != nil, "result", "the member has no recorded result to correct")
+	v.Required("reason", c.Reason)
+	v.MaxLength("reason", c.Reason, 1000)
+	v.NonNegative("proposed_result", c.ProposedResult)
+	if m.Result != nil {
+		v.Check(c.ProposedResult != *m.Result, "proposed_result", "must differ from the current result")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	pending, err := s.repo.HasPendingCorrection(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, fmt.Errorf("member %d already has a pending correction: %w", memberID, domain.ErrConflict)
+	}
+
+	c.ID = 0
+	c.MemberID = memberID
+	c.PreviousResult = m.Result
+	c.Status = CorrectionPending
+	c.RequestedBy = u.ID
+	c.RequestedAt = time.Now().UTC()
+	c.ReviewedBy, c.ReviewedAt, c.ReviewNote = nil, nil, nil
+	if err := s.repo.SaveCorrection(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetCorrections lists corrections, newest first.
+func (s *Service) GetCorrections(filter CorrectionFilter, req domain.PageRequest) (*domain.Page[*Correction], error) {
+	v := &domain.Validator{}
+	switch filter.Status {
+	case "", CorrectionPending, CorrectionApproved, CorrectionRejected:
+	default:
+		v.Check(false, "status", "must be pending, approved or rejected")
+	}
+	req.Normalize()
+	req.Validate(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if filter.MemberID > 0 {
+		if _, err := s.repo.GetMemberByID(filter.MemberID); err != nil {
+			return nil, err
+		}
+	}
+
+	corrections, total, err := s.repo.GetCorrections(filter, req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(corrections, total, req, func(c *Correction) int { return c.ID }), nil
+}
+
+// ApproveCorrection applies a pending correction to its member. Only a lab
+// manager other than the requester may approve; issued reports of the
+// member's family are flagged as superseded.
+func (s *Service) ApproveCorrection(ctx context.Context, correctionID int, note *string) (*Correction, error) {
+	c, reviewer, err := s.reviewable(ctx, correctionID)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.repo.GetMemberByID(c.MemberID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.review(c, reviewer, CorrectionApproved, note)
+	if err := s.repo.ApproveCorrection(c); err != nil {
+		return nil, err
+	}
+
+	after := *before
+	after.Result = &c.ProposedResult
+	if err := s.audit.Record(ctx, audit.EntityMember, c.MemberID, audit.ActionUpdate, before, after); err != nil {
+		return nil, err
+	}
+	s.results.ResultRecorded(ctx, &after)
+	return c, nil
+}
+
+// RejectCorrection closes a pending correction leaving the member untouched.
+func (s *Service) RejectCorrection(ctx context.Context, correctionID int, note *string) (*Correction, error) {
+	c, reviewer, err := s.reviewable(ctx, correctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.review(c, reviewer, CorrectionRejected, note)
+	if err := s.repo.RejectCorrection(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// reviewable loads a pending correction the authenticated user may review.
+func (s *Service) reviewable(ctx context.Context, correctionID int) (*Correction, *user.User, error) {
+	reviewer, err := user.RequireRole(ctx, user.RoleLabManager)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := s.repo.GetCorrectionByID(correctionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.Status != CorrectionPending {
+		return nil, nil, fmt.Errorf("correction %d is already %s: %w", c.ID, c.Status, domain.ErrConflict)
+	}
+	if c.RequestedBy == reviewer.ID {
+		return nil, nil, fmt.Errorf("a correction cannot be reviewed by its requester: %w", domain.ErrForbidden)
+	}
+	return c, reviewer, nil
+}
+
+func (s *Service) review(c *Correction, reviewer *user.User, status string, note *string) {
+	now := time.Now().UTC()
+	c.Status = status
+	c.ReviewedBy = &reviewer.ID
+	c.ReviewedAt = &now
+	c.ReviewNote = note
+}
@@ -0,0 +1,268 @@
+package member
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/audit"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/user"
+)
+
+// Quantity a load curve is plotted against: elapsed seconds or platen
+// displacement in mm.
+const (
+	CurveAxisTime         = "time"
+	CurveAxisDisplacement = "displacement"
+)
+
+// maxCurvePoints bounds the samples stored per curve. Presses log at up to
+// 50 Hz, so this covers several minutes of loading.
+const maxCurvePoints = 20000
+
+// MaxCurvePeakDifference is the largest relative difference allowed between
+// the peak of a curve and the recorded result. Sampling may miss the exact
+// peak slightly; more than this means the curve belongs to another break.
+const MaxCurvePeakDifference = 0.02
+
+// curveColumns lists the header names presses use for each column of a
+// curve export, lowercased. The first matching column wins.
+var curveColumns = map[string][]string{
+	CurveAxisTime:         {"time", "time_s", "time (s)", "t", "elapsed", "tiempo", "tiempo_s", "tiempo (s)"},
+	CurveAxisDisplacement: {"displacement", "displacement_mm", "displacement (mm)", "position", "stroke", "desplazamiento", "desplazamiento_mm", "desplazamiento (mm)", "deformacion", "deformación"},
+	"load":                {"load", "load_kn", "load (kn)", "force", "force_kn", "carga", "carga_kn", "carga (kn)", "fuerza"},
+}
+
+// CurvePoint is a sample of a load curve: the load in kN at X seconds or
+// mm, depending on the curve's axis.
+type CurvePoint struct {
+	X    float64 `json:"x"`
+	Load float64 `json:"load"`
+}
+
+// Curve is the load series captured by the press while fracturing a member.
+type Curve struct {
+	MemberID   int          `db:"member_id" json:"member_id"`
+	Axis       string       `db:"axis" json:"axis"`
+	Points     []CurvePoint `db:"-" json:"points"`
+	PeakLoad   float64      `db:"peak_load" json:"peak_load"`
+	RecordedBy int          `db:"recorded_by" json:"recorded_by"`
+	RecordedAt time.Time    `db:"recorded_at" json:"recorded_at"`
+}
+
+// ParseCurveExport reads a curve exported by a press as CSV or TXT. The
+// first line is a header with a time or displacement column and a load
+// column; fields are separated by commas, semicolons or tabs and numbers
+// may use a decimal comma.
+func ParseCurveExport(r io.Reader) (*Curve, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimPrefix(string(content), "\ufeff")
+	firstLine := text
+	if i := strings.IndexByte(firstLine, '\n'); i >= 0 {
+		firstLine = firstLine[:i]
+	}
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = exportDelimiter(firstLine)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, invalidCurve(err.Error())
+	}
+	if len(records) < 2 {
+		return nil, invalidCurve("has no data rows")
+	}
+
+	columns := curveColumnIndexes(records[0])
+	curve := &Curve{Axis: CurveAxisDisplacement}
+	x := columns[CurveAxisDisplacement]
+	if x < 0 {
+		curve.Axis, x = CurveAxisTime, columns[CurveAxisTime]
+	}
+	if x < 0 || columns["load"] < 0 {
+		return nil, invalidCurve("needs a time or displacement column and a load column")
+	}
+
+	for i, record := range records[1:] {
+		if isBlank(record) {
+			continue
+		}
+		xValue, err := parseExportNumber(field(record, x))
+		if err != nil {
+			return nil, invalidCurve(fmt.Sprintf("line %d: %s is not a number", i+2, curve.Axis))
+		}
+		load, err := parseExportNumber(field(record, columns["load"]))
+		if err != nil {
+			return nil, invalidCurve(fmt.Sprintf("line %d: load is not a number", i+2))
+		}
+		curve.Points = append(curve.Points, CurvePoint{X: xValue, Load: load})
+	}
+	return curve, nil
+}
+
+func curveColumnIndexes(header []string) map[string]int {
+	indexes := map[string]int{}
+	for key, names := range curveColumns {
+		indexes[key] = -1
+	names:
+		for _, name := range names {
+			for i, h := range header {
+				if strings.EqualFold(strings.TrimSpace(h), name) {
+					indexes[key] = i
+					break names
+				}
+			}
+		}
+	}
+	return indexes
+}
+
+func invalidCurve(message string) error {
+	v := &domain.Validator{}
+	v.Check(false, "file", message)
+	return v.Err()
+}
+
+// curveSnapshot is what the audit trail keeps of a curve; the points
+// themselves are too large to copy into every entry.
+type curveSnapshot struct {
+	Axis       string    `json:"axis"`
+	PointCount int       `json:"point_count"`
+	PeakLoad   float64   `json:"peak_load"`
+	RecordedBy int       `json:"recorded_by"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+func snapshotCurve(c *Curve) *curveSnapshot {
+	if c == nil {
+		return nil
+	}
+	return &curveSnapshot{Axis: c.Axis, PointCount: len(c.Points), PeakLoad: c.PeakLoad, RecordedBy: c.RecordedBy, RecordedAt: c.RecordedAt}
+}
+
+// SaveCurve attaches a load curve to a fractured member on behalf of the
+// authenticated user. Its peak must match the recorded result. A curve
+// already stored is part of the locked result: it can only be replaced once
+// a result correction of the member has been approved after it.
+func (s *Service) SaveCurve(ctx context.Context, memberID int, c *Curve) (*Curve, error) {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+
+	m, err := s.repo.GetMemberByID(memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &domain.Validator{}
+	v.Check(m.Result != nil, "member_id", "the member has not been fractured yet")
+	validateCurve(v, c)
+	if m.Result != nil && !v.HasErrors("points") {
+		checkCurvePeak(v, c, *m.Result)
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	previous, err := s.repo.GetCurve(memberID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		previous = nil
+	case err != nil:
+		return nil, err
+	default:
+		corrected, err := s.correctedSince(memberID, previous.RecordedAt)
+		if err != nil {
+			return nil, err
+		}
+		if !corrected {
+			return nil, fmt.Errorf("member %d already has a load curve; replacing it needs an approved result correction: %w", memberID, domain.ErrConflict)
+		}
+	}
+
+	c.MemberID = memberID
+	c.RecordedBy = u.ID
+	c.RecordedAt = time.Now().UTC()
+	if err := s.repo.SaveCurve(c); err != nil {
+		return nil, err
+	}
+	action := audit.ActionCreate
+	if previous != nil {
+		action = audit.ActionUpdate
+	}
+	if err := s.audit.Record(ctx, audit.EntityCurve, memberID, action, snapshotCurve(previous), snapshotCurve(c)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// correctedSince reports whether a result correction of the member was
+// approved after since.
+func (s *Service) correctedSince(memberID int, since time.Time) (bool, error) {
+	latest, _, err := s.repo.GetCorrections(CorrectionFilter{MemberID: memberID, Status: CorrectionApproved}, domain.PageRequest{Page: 1, PageSize: 1})
+	if err != nil {
+		return false, err
+	}
+	return len(latest) > 0 && latest[0].ReviewedAt != nil && latest[0].ReviewedAt.After(since), nil
+}
+
+// checkCurvePeak rejects a curve whose peak is not the recorded result.
+func checkCurvePeak(v *domain.Validator, c *Curve, result float64) {
+	v.Check(math.Abs(c.PeakLoad-result) <= MaxCurvePeakDifference*result, "points",
+		fmt.Sprintf("peak load %.2f kN differs from the recorded result %.2f kN by more than 2%%", c.PeakLoad, result))
+}
+
+// validateCurve checks the axis and points of c and sets its peak load.
+func validateCurve(v *domain.Validator, c *Curve) {
+	v.Check(c.Axis == CurveAxisTime || c.Axis == CurveAxisDisplacement, "axis", "must be time or displacement")
+	v.Check(len(c.Points) >= 2, "points", "must have at least 2 points")
+	v.Check(len(c.Points) <= maxCurvePoints, "points", fmt.Sprintf("must have at most %d points", maxCurvePoints))
+	for i, p := range c.Points {
+		if math.IsNaN(p.X) || math.IsInf(p.X, 0) || math.IsNaN(p.Load) || math.IsInf(p.Load, 0) {
+			v.Check(false, "points", fmt.Sprintf("point %d is not a finite number", i))
+			break
+		}
+		if p.Load < 0 {
+			v.Check(false, "points", fmt.Sprintf("point %d has a negative load", i))
+			break
+		}
+		if c.Axis == CurveAxisTime && i > 0 && p.X < c.Points[i-1].X {
+			v.Check(false, "points", fmt.Sprintf("point %d goes back in time", i))
+			break
+		}
+	}
+
+	c.PeakLoad = 0
+	for _, p := range c.Points {
+		c.PeakLoad = math.Max(c.PeakLoad, p.Load)
+	}
+}
+
+// GetCurve returns the load curve of a member. It wraps domain.ErrNotFound
+// when the member has none.
+func (s *Service) GetCurve(memberID int) (*Curve, error) {
+	if _, err := s.repo.GetMemberByID(memberID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetCurve(memberID)
+}
+
+// GetCurves returns the load curves of the given members, by member id.
+// Members without a curve are left out.
+func (s *Service) GetCurves(memberIDs []int) (map[int]*Curve, error) {
+	if len(memberIDs) == 0 {
+		return map[int]*Curve{}, nil
+	}
+	return s.repo.GetCurves(memberIDs)
+}
@@ -0,0 +1,162 @@
+package member
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/user"
+)
+
+// Custody events, in the order a specimen normally goes through them. A
+// specimen arrives once, may be demolded once, is placed in curing and
+// moved any number of times, and is removed for the break last.
+const (
+	CustodyArrived  = "arrived"
+	CustodyDemolded = "demolded"
+	CustodyCuring   = "curing"
+	CustodyMoved    = "moved"
+	CustodyRemoved  = "removed"
+)
+
+// Curing temperatures outside this range are rejected as typos. ASTM C511
+// curing requires 23 ± 2 °C, but readings out of spec must still be kept.
+const (
+	minCuringTemperature = -10
+	maxCuringTemperature = 60
+)
+
+// CustodyEvent records where a specimen was, or what was done to it, at a
+// point in time and who recorded it.
+type CustodyEvent struct {
+	ID       int    `db:"id" json:"id"`
+	MemberID int    `db:"member_id" json:"member_id"`
+	Event    string `db:"event" json:"event"`
+	// Location is the room, shelf or curing tank the specimen is at after
+	// the event. Demolding keeps the previous location when left empty.
+	Location     string    `db:"location" json:"location"`
+	TemperatureC *float64  `db:"temperature_c" json:"temperature_c"`
+	Notes        string    `db:"notes" json:"notes"`
+	RecordedBy   int       `db:"recorded_by" json:"recorded_by"`
+	OccurredAt   time.Time `db:"occurred_at" json:"occurred_at"`
+}
+
+// RecordCustodyEvent appends an event to the custody chain of memberID on
+// behalf of the authenticated user. OccurredAt defaults to now.
+func (s *Service) RecordCustodyEvent(ctx context.Context, memberID int, e *CustodyEvent) (*CustodyEvent, error) {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+
+	m, err := s.repo.GetMemberByID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	last, err := s.repo.GetLastCustodyEvent(memberID)
+	if err != nil {
+		return nil, err
+	}
+	demolded, err := s.repo.HasCustodyEvent(memberID, CustodyDemolded)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = now
+	}
+	if e.Event == CustodyDemolded && e.Location == "" && last != nil {
+		e.Location = last.Location
+	}
+
+	v := &domain.Validator{}
+	switch e.Event {
+	case CustodyArrived:
+		v.Check(last == nil, "event", "the specimen has already arrived")
+	case CustodyDemolded, CustodyCuring, CustodyMoved, CustodyRemoved:
+		v.Check(last != nil, "event", "the specimen has not arrived yet")
+		v.Check(last == nil || last.Event != CustodyRemoved, "event", "the specimen was already removed")
+		if e.Event == CustodyDemolded {
+			v.Check(!demolded, "event", "the specimen was already demolded")
+		}
+	default:
+		v.Check(false, "event", "must be arrived, demolded, curing, moved or removed")
+	}
+	if e.Event != CustodyRemoved {
+		v.Required("location", e.Location)
+	}
+	v.MaxLength("location", e.Location, 100)
+	v.MaxLength("notes", e.Notes, 1000)
+	if e.TemperatureC != nil {
+		v.Check(*e.TemperatureC >= minCuringTemperature && *e.TemperatureC <= maxCuringTemperature,
+			"temperature_c", fmt.Sprintf("must be between %d and %d", minCuringTemperature, maxCuringTemperature))
+	}
+	v.Check(!e.OccurredAt.After(now), "occurred_at", "must not be in the future")
+	if last != nil {
+		v.Check(!e.OccurredAt.Before(last.OccurredAt), "occurred_at", "must not be before the previous custody event")
+	}
+	if m.FracturedAt != nil && e.Event != CustodyRemoved {
+		v.Check(!e.OccurredAt.After(*m.FracturedAt), "occurred_at", "must not be after the specimen was fractured")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	e.ID = 0
+	e.MemberID = memberID
+	e.RecordedBy = u.ID
+	if err := s.repo.SaveCustodyEvent(e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// GetCustodyEvents lists the custody chain of a member, oldest first.
+func (s *Service) GetCustodyEvents(memberID int, req domain.PageRequest) (*domain.Page[*CustodyEvent], error) {
+	if err := req.Prepare(); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetMemberByID(memberID); err != nil {
+		return nil, err
+	}
+	events, total, err := s.repo.GetCustodyEvents(memberID, req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(events, total, req, func(e *CustodyEvent) int { return e.ID }), nil
+}
+
+// GetCurrentCustody answers where a member is: its latest custody event. It
+// wraps domain.ErrNotFound when the member has none yet.
+func (s *Service) GetCurrentCustody(memberID int) (*CustodyEvent, error) {
+	if _, err := s.repo.GetMemberByID(memberID); err != nil {
+		return nil, err
+	}
+	last, err := s.repo.GetLastCustodyEvent(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if last == nil {
+		return nil, fmt.Errorf("member %d has no custody events: %w", memberID, domain.ErrNotFound)
+	}
+	return last, nil
+}
+
+// GetSpecimensAt lists the latest custody event of every specimen currently
+// at location and not yet removed.
+func (s *Service) GetSpecimensAt(location string, req domain.PageRequest) (*domain.Page[*CustodyEvent], error) {
+	req.Normalize()
+	v := &domain.Validator{}
+	req.Validate(v)
+	v.Required("location", location)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	events, total, err := s.repo.GetSpecimensAt(location, req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(events, total, req, func(e *CustodyEvent) int { return e.ID }), nil
+}
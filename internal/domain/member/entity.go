@@ -17,4 +17,66 @@ type Member struct {
 	FractureDays   *int       `db:"fracture_days" json:"fracture_days"`
 	OperativeID    *int       `db:"operative" json:"-"`
 	FractureType   *string    `db:"fracture_type" json:"fracture_type"`
+
+	// Measurements taken on the specimen, in cm and kg. Diameter1CM and
+	// Diameter2CM are two perpendicular readings on cylinders and cores.
+	Diameter1CM *float64 `db:"diameter_1_cm" json:"diameter_1_cm"`
+	Diameter2CM *float64 `db:"diameter_2_cm" json:"diameter_2_cm"`
+	HeightCM    *float64 `db:"height_cm" json:"height_cm"`
+	MassKG      *float64 `db:"mass_kg" json:"mass_kg"`
+	// Preparation checks: whether the ends are perpendicular to the axis and
+	// plane within tolerance, and how they were capped.
+	Perpendicular *bool   `db:"perpendicular" json:"perpendicular"`
+	CappingMethod *string `db:"capping_method" json:"capping_method"`
+	PlanenessOK   *bool   `db:"planeness_ok" json:"planeness_ok"`
+	// MachineID is the testing machine the member was broken on.
+	MachineID *int `db:"machine_id" json:"machine_id"`
+	// Density in kg/m³, computed from the mass and the specimen volume when
+	// the member is loaded with its family.
+	Density *float64 `db:"-" json:"density,omitempty"`
+}
+
+// Capping methods used to prepare the specimen ends.
+const (
+	CappingSulfur   = "sulfur"
+	CappingNeoprene = "neoprene"
+	CappingGround   = "ground"
+	CappingNone     = "none"
+)
+
+func ValidCappingMethod(method string) bool {
+	switch method {
+	case CappingSulfur, CappingNeoprene, CappingGround, CappingNone:
+		return true
+	}
+	return false
+}
+
+// MaxDiameterDifference is the largest relative difference allowed between
+// the two diameter readings (ASTM C39).
+const MaxDiameterDifference = 0.02
+
+// MeasuredDiameter averages the diameter readings available, or returns
+// false when there is none.
+func (m Member) MeasuredDiameter() (float64, bool) {
+	switch {
+	case m.Diameter1CM != nil && m.Diameter2CM != nil:
+		return (*m.Diameter1CM + *m.Diameter2CM) / 2, true
+	case m.Diameter1CM != nil:
+		return *m.Diameter1CM, true
+	case m.Diameter2CM != nil:
+		return *m.Diameter2CM, true
+	}
+	return 0, false
+}
+
+// SpecimenCore is the family specimen type of drilled cores.
+const SpecimenCore = "core"
+
+// FamilyShape is the part of a family member validation depends on: its
+// specimen type and nominal radius and height in cm.
+type FamilyShape struct {
+	SpecimenType string  `db:"specimen_type"`
+	Radius       float64 `db:"radius"`
+	Height       float64 `db:"height"`
 }
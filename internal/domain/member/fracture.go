@@ -0,0 +1,32 @@
+package member
+
+// FractureType is a failure pattern of the catalogue. Code is what members
+// store in fracture_type.
+type FractureType struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Sketch is the file name of the pattern drawing under
+	// resources/fracture_types, if there is one.
+	Sketch string `json:"sketch,omitempty"`
+}
+
+// FractureTypes is the ASTM C39 catalogue of cylinder failure patterns.
+var FractureTypes = []FractureType{
+	{Code: "1", Name: "Tipo 1", Description: "Conos razonablemente bien formados en ambos extremos, fisuras a través de los cabezales de menos de 25 mm", Sketch: "1.svg"},
+	{Code: "2", Name: "Tipo 2", Description: "Cono bien formado en un extremo, fisuras verticales a través de los cabezales, cono no bien definido en el otro extremo", Sketch: "2.svg"},
+	{Code: "3", Name: "Tipo 3", Description: "Fisuras verticales columnares en ambos extremos, conos no bien formados", Sketch: "3.svg"},
+	{Code: "4", Name: "Tipo 4", Description: "Fractura diagonal sin fisuras a través de los extremos", Sketch: "4.svg"},
+	{Code: "5", Name: "Tipo 5", Description: "Fracturas en los lados, en las partes superior o inferior", Sketch: "5.svg"},
+	{Code: "6", Name: "Tipo 6", Description: "Similar al tipo 5 pero el extremo del cilindro es puntiagudo", Sketch: "6.svg"},
+}
+
+// FractureTypeByCode looks code up in the catalogue.
+func FractureTypeByCode(code string) (FractureType, bool) {
+	for _, t := range FractureTypes {
+		if t.Code == code {
+			return t, true
+		}
+	}
+	return FractureType{}, false
+}
@@ -0,0 +1,389 @@
+package member
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/audit"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/user"
+)
+
+// Outcome of each row of an ingested machine export.
+const (
+	IngestRecorded  = "recorded"
+	IngestUnmatched = "unmatched"
+	IngestDuplicate = "duplicate"
+	IngestInvalid   = "invalid"
+	IngestRejected  = "rejected"
+)
+
+// exportColumns lists the header names testing machines use for each field
+// we read, lowercased. The first matching column wins.
+var exportColumns = map[string][]string{
+	"code":      {"specimen", "specimen_id", "sample", "sample_id", "id", "code", "barcode", "especimen", "espécimen", "muestra", "codigo", "código"},
+	"load":      {"peak_load", "peak load", "max_load", "max load", "load_kn", "load", "fmax", "carga_maxima", "carga máxima", "carga"},
+	"timestamp": {"timestamp", "date_time", "datetime", "test_time", "date", "fecha", "fecha_hora", "hora"},
+	"curve":     {"curve", "load_curve", "load curve", "curva", "curva_carga", "curva de carga"},
+}
+
+// exportTimeLayouts are the timestamp formats accepted, in the machine's
+// local time unless they carry a zone.
+var exportTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"2006/01/02 15:04:05",
+}
+
+// ExportRow is a data row of a machine export.
+type ExportRow struct {
+	Line        int
+	Code        string
+	Load        float64
+	FracturedAt *time.Time
+	// Curve is the load curve of the row, when the export has one.
+	Curve *Curve
+	// Err explains why the row could not be read.
+	Err string
+}
+
+// IngestOptions tune an ingestion.
+type IngestOptions struct {
+	// MachineID links every recorded result to a testing machine, which must
+	// be calibrated on each fracture date. It is required.
+	MachineID *int
+	// DryRun matches and validates rows without recording anything.
+	DryRun bool
+}
+
+// IngestRow reports what happened to one row.
+type IngestRow struct {
+	Line        int        `json:"line"`
+	Code        string     `json:"code"`
+	MemberID    *int       `json:"member_id,omitempty"`
+	Load        float64    `json:"load"`
+	FracturedAt *time.Time `json:"fractured_at,omitempty"`
+	CurvePoints int        `json:"curve_points,omitempty"`
+	Status      string     `json:"status"`
+	Message     string     `json:"message,omitempty"`
+}
+
+// IngestResult summarizes an ingestion.
+type IngestResult struct {
+	DryRun bool           `json:"dry_run"`
+	Counts map[string]int `json:"counts"`
+	Rows   []IngestRow    `json:"rows"`
+}
+
+// ParseMachineExport reads a CSV or TXT export. The first line is a header;
+// fields are separated by commas, semicolons or tabs, and loads may use a
+// decimal comma. A load curve column holds "seconds:kN" samples separated by
+// spaces or "|". Rows that cannot be read are returned with Err set.
+func ParseMachineExport(r io.Reader) ([]ExportRow, error) {
+	buffered := bufio.NewReader(r)
+	header, err := buffered.Peek(4096)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+	firstLine := string(header)
+	if i := strings.IndexByte(firstLine, '\n'); i >= 0 {
+		firstLine = firstLine[:i]
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = exportDelimiter(firstLine)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, invalidExport(err.Error())
+	}
+	if len(records) < 2 {
+		return nil, invalidExport("has no data rows")
+	}
+
+	columns := exportColumnIndexes(records[0])
+	if columns["code"] < 0 || columns["load"] < 0 {
+		return nil, invalidExport("needs a specimen id and a peak load column")
+	}
+
+	var rows []ExportRow
+	for i, record := range records[1:] {
+		if isBlank(record) {
+			continue
+		}
+		row := ExportRow{Line: i + 2, Code: strings.TrimSpace(field(record, columns["code"]))}
+		load, err := parseExportNumber(field(record, columns["load"]))
+		switch {
+		case row.Code == "":
+			row.Err = "missing specimen id"
+		case err != nil:
+			row.Err = "peak load is not a number"
+		case load <= 0:
+			row.Err = "peak load must be greater than zero"
+		}
+		row.Load = load
+		if columns["timestamp"] >= 0 && row.Err == "" {
+			raw := strings.TrimSpace(field(record, columns["timestamp"]))
+			if raw != "" {
+				t, ok := parseExportTime(raw)
+				if !ok {
+					row.Err = "timestamp is not a known date format"
+				}
+				row.FracturedAt = t
+			}
+		}
+		if columns["curve"] >= 0 && row.Err == "" {
+			if raw := strings.TrimSpace(field(record, columns["curve"])); raw != "" {
+				curve, ok := parseExportCurve(raw)
+				if !ok {
+					row.Err = "load curve is not a list of seconds:kN samples"
+				}
+				row.Curve = curve
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseExportCurve reads a load curve cell as a time curve.
+func parseExportCurve(raw string) (*Curve, bool) {
+	samples := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == '|' || unicode.IsSpace(r)
+	})
+	curve := &Curve{Axis: CurveAxisTime, Points: make([]CurvePoint, 0, len(samples))}
+	for _, sample := range samples {
+		x, load, ok := strings.Cut(sample, ":")
+		if !ok {
+			return nil, false
+		}
+		xValue, err := parseExportNumber(x)
+		if err != nil {
+			return nil, false
+		}
+		loadValue, err := parseExportNumber(load)
+		if err != nil {
+			return nil, false
+		}
+		curve.Points = append(curve.Points, CurvePoint{X: xValue, Load: loadValue})
+	}
+	return curve, true
+}
+
+func exportDelimiter(header string) rune {
+	best, count := ',', strings.Count(header, ",")
+	for _, d := range []rune{';', '\t'} {
+		if n := strings.Count(header, string(d)); n > count {
+			best, count = d, n
+		}
+	}
+	return best
+}
+
+func exportColumnIndexes(header []string) map[string]int {
+	indexes := map[string]int{}
+	for key, names := range exportColumns {
+		indexes[key] = -1
+	names:
+		for _, name := range names {
+			for i, h := range header {
+				if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")), name) {
+					indexes[key] = i
+					break names
+				}
+			}
+		}
+	}
+	return indexes
+}
+
+func field(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+	return record[i]
+}
+
+func isBlank(record []string) bool {
+	for _, f := range record {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func parseExportNumber(raw string) (float64, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.Contains(raw, ",") && !strings.Contains(raw, ".") {
+		raw = strings.Replace(raw, ",", ".", 1)
+	}
+	return strconv.ParseFloat(raw, 64)
+}
+
+func parseExportTime(raw string) (*time.Time, bool) {
+	for _, layout := range exportTimeLayouts {
+		if t, err := time.ParseInLocation(layout, raw, time.Local); err == nil {
+			t = t.UTC()
+			return &t, true
+		}
+	}
+	return nil, false
+}
+
+func invalidExport(message string) error {
+	v := &domain.Validator{}
+	v.Check(false, "file", message)
+	return v.Err()
+}
+
+// IngestMachineExport records the peak loads of a machine export as the
+// results of the matching members, with their load curves when the export
+// has them, on behalf of the authenticated user.
+// Rows are matched by member id or label code. Members that already have a
+// result are reported as duplicates and left untouched: changing a result
+// needs a correction.
+func (s *Service) IngestMachineExport(ctx context.Context, r io.Reader, opts IngestOptions) (*IngestResult, error) {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	if opts.MachineID == nil {
+		v := &domain.Validator{}
+		v.Check(false, "machine_id", "is required")
+		return nil, v.Err()
+	}
+
+	rows, err := ParseMachineExport(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &IngestResult{DryRun: opts.DryRun, Counts: map[string]int{}}
+	seen := map[int]int{}
+	now := time.Now().UTC()
+	for _, row := range rows {
+		out, err := s.ingestRow(ctx, row, opts, u, seen, now)
+		if err != nil {
+			return nil, err
+		}
+		result.Counts[out.Status]++
+		result.Rows = append(result.Rows, out)
+	}
+	return result, nil
+}
+
+func (s *Service) ingestRow(ctx context.Context, row ExportRow, opts IngestOptions, u *user.User, seen map[int]int, now time.Time) (IngestRow, error) {
+	out := IngestRow{Line: row.Line, Code: row.Code, Load: row.Load, FracturedAt: row.FracturedAt}
+	if row.Err != "" {
+		out.Status, out.Message = IngestInvalid, row.Err
+		return out, nil
+	}
+	if out.FracturedAt == nil {
+		out.FracturedAt = &now
+	}
+	if out.FracturedAt.After(now) {
+		out.Status, out.Message = IngestInvalid, "timestamp is in the future"
+		return out, nil
+	}
+
+	match, err := s.LookupLabel(row.Code)
+	var invalidCode *domain.ValidationError
+	switch {
+	case errors.Is(err, domain.ErrNotFound) || errors.As(err, &invalidCode):
+		out.Status, out.Message = IngestUnmatched, "no member matches the specimen id"
+		return out, nil
+	case err != nil:
+		out.Status, out.Message = IngestRejected, err.Error()
+		return out, nil
+	}
+	m := match.Member
+	out.MemberID = &m.ID
+	if line, ok := seen[m.ID]; ok {
+		out.Status, out.Message = IngestDuplicate, fmt.Sprintf("member already in line %d", line)
+		return out, nil
+	}
+	seen[m.ID] = row.Line
+	if m.Result != nil {
+		out.Status, out.Message = IngestDuplicate, "member already has a result"
+		return out, nil
+	}
+
+	if row.Curve != nil {
+		out.CurvePoints = len(row.Curve.Points)
+		v := &domain.Validator{}
+		validateCurve(v, row.Curve)
+		if !v.HasErrors("points") {
+			checkCurvePeak(v, row.Curve, row.Load)
+		}
+		var invalid *domain.ValidationError
+		if errors.As(v.Err(), &invalid) {
+			out.Status, out.Message = IngestInvalid, "load curve "+invalid.Errors[0].Field+" "+invalid.Errors[0].Message
+			return out, nil
+		}
+		row.Curve.MemberID = m.ID
+		row.Curve.RecordedBy = u.ID
+		row.Curve.RecordedAt = now
+	}
+
+	inService, calibrated, err := s.repo.MachineCalibration(*opts.MachineID, *out.FracturedAt)
+	switch {
+	case err != nil:
+		out.Status, out.Message = IngestRejected, err.Error()
+		return out, nil
+	case !inService:
+		out.Status, out.Message = IngestRejected, "machine does not exist or is retired"
+		return out, nil
+	case !calibrated:
+		out.Status, out.Message = IngestRejected, "machine is out of calibration on the fracture date"
+		return out, nil
+	}
+
+	if opts.DryRun {
+		out.Status = IngestRecorded
+		return out, nil
+	}
+
+	before := *m
+	m.Result = &out.Load
+	m.FracturedAt = out.FracturedAt
+	m.OperativeID = &u.ID
+	m.MachineID = opts.MachineID
+	if err := s.repo.RecordResult(m, row.Curve); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			out.Status, out.Message = IngestDuplicate, "member already has a result"
+		} else {
+			out.Status, out.Message = IngestRejected, err.Error()
+		}
+		return out, nil
+	}
+	after, err := s.repo.GetMemberByID(m.ID)
+	if err != nil {
+		return out, err
+	}
+	if err := s.audit.Record(ctx, audit.EntityMember, m.ID, audit.ActionUpdate, before, after); err != nil {
+		return out, err
+	}
+	if row.Curve != nil {
+		if err := s.audit.Record(ctx, audit.EntityCurve, m.ID, audit.ActionCreate, nil, snapshotCurve(row.Curve)); err != nil {
+			return out, err
+		}
+	}
+	s.results.ResultRecorded(ctx, m)
+	out.Status = IngestRecorded
+	return out, nil
+}
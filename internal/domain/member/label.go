@@ -0,0 +1,94 @@
+package member
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+)
+
+// LabelRef is what a specimen label encodes: the member, its project and,
+// when already scheduled, its fracture date.
+type LabelRef struct {
+	MemberID     int        `json:"member_id"`
+	ProjectID    int        `json:"project_id"`
+	FractureDate *time.Time `json:"fracture_date"`
+}
+
+// LabelMatch is the member a scanned label resolves to.
+type LabelMatch struct {
+	Label     LabelRef `json:"label"`
+	Member    *Member  `json:"member"`
+	ProjectID int      `json:"project_id"`
+}
+
+// labelCode matches codes like M123P4D20261019. Technicians typing a code
+// by hand may also enter the bare member id.
+var labelCode = regexp.MustCompile(`^M(\d+)P(\d+)(?:D(\d{8}))?$`)
+
+// LabelCode is the short, Code128 friendly text printed on the label of m.
+func LabelCode(m Member, projectID int) string {
+	code := fmt.Sprintf("M%dP%d", m.ID, projectID)
+	if m.DateOfFracture != nil {
+		code += "D" + m.DateOfFracture.Format("20060102")
+	}
+	return code
+}
+
+// ParseLabelCode reads a code printed by LabelCode, or a bare member id, in
+// which case ProjectID is zero.
+func ParseLabelCode(code string) (LabelRef, error) {
+	if id, err := strconv.Atoi(code); err == nil && id > 0 {
+		return LabelRef{MemberID: id}, nil
+	}
+
+	parts := labelCode.FindStringSubmatch(code)
+	if parts == nil {
+		return LabelRef{}, invalidLabel()
+	}
+	ref := LabelRef{}
+	ref.MemberID, _ = strconv.Atoi(parts[1])
+	ref.ProjectID, _ = strconv.Atoi(parts[2])
+	if parts[3] != "" {
+		date, err := time.Parse("20060102", parts[3])
+		if err != nil {
+			return LabelRef{}, invalidLabel()
+		}
+		ref.FractureDate = &date
+	}
+	if ref.MemberID == 0 || ref.ProjectID == 0 {
+		return LabelRef{}, invalidLabel()
+	}
+	return ref, nil
+}
+
+func invalidLabel() error {
+	v := &domain.Validator{}
+	v.Check(false, "code", "is not a specimen label")
+	return v.Err()
+}
+
+// LookupLabel resolves a scanned label to its member. Labels whose project
+// does not match the member's are reported as not found, since they were
+// printed for another specimen.
+func (s *Service) LookupLabel(code string) (*LabelMatch, error) {
+	ref, err := ParseLabelCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := s.repo.GetMemberByID(ref.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	projectID, err := s.repo.FamilyProjectID(m.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if ref.ProjectID != 0 && ref.ProjectID != projectID {
+		return nil, fmt.Errorf("label %s does not match member %d: %w", code, m.ID, domain.ErrNotFound)
+	}
+	return &LabelMatch{Label: ref, Member: m, ProjectID: projectID}, nil
+}
@@ -1,5 +1,68 @@
 package member
 
+import (
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+)
+
 type Repository interface {
 	SaveMembers([]*Member) ([]*Member, error)
-}
\ No newline at end of file
+	// GetMembers lists members. A zero familyID lists every member.
+	GetMembers(familyID int, req domain.PageRequest) ([]*Member, int, error)
+	// GetMemberByID wraps domain.ErrNotFound when the member does not exist.
+	GetMemberByID(ID int) (*Member, error)
+	// FamilyShapes returns the shape of each of the given families that
+	// exists, keyed by family id.
+	FamilyShapes(familyIDs []int) (map[int]FamilyShape, error)
+	// RecordResult stores the result, fracture time, operative and machine
+	// of a member not fractured yet, and its load curve when not nil, in one
+	// transaction. The nominal fracture date and age are kept. It wraps
+	// domain.ErrConflict when the member already has a result.
+	RecordResult(m *Member, curve *Curve) error
+	// FamilyProjectID returns the project a family belongs to, 0 when it
+	// has none.
+	FamilyProjectID(familyID int) (int, error)
+	// MachineCalibration reports whether machineID is a testing machine in
+	// service and whether it, and every load cell mounted on it, had a valid
+	// calibration certificate on day.
+	MachineCalibration(machineID int, day time.Time) (inService, calibrated bool, err error)
+
+	SaveCorrection(c *Correction) error
+	// GetCorrectionByID wraps domain.ErrNotFound when it does not exist.
+	GetCorrectionByID(ID int) (*Correction, error)
+	GetCorrections(filter CorrectionFilter, req domain.PageRequest) ([]*Correction, int, error)
+	HasPendingCorrection(memberID int) (bool, error)
+	// ApproveCorrection stores the review, sets the corrected result on the
+	// member and supersedes the family's issued reports, atomically. It
+	// wraps domain.ErrConflict when the correction is no longer pending.
+	ApproveCorrection(c *Correction) error
+	// RejectCorrection stores the review. It wraps domain.ErrConflict when
+	// the correction is no longer pending.
+	RejectCorrection(c *Correction) error
+
+	SaveCustodyEvent(e *CustodyEvent) error
+	// GetCustodyEvents lists the custody chain of a member, oldest first.
+	GetCustodyEvents(memberID int, req domain.PageRequest) ([]*CustodyEvent, int, error)
+	// GetLastCustodyEvent returns nil when the member has no custody events.
+	GetLastCustodyEvent(memberID int) (*CustodyEvent, error)
+	HasCustodyEvent(memberID int, event string) (bool, error)
+	// GetSpecimensAt returns the latest custody event of the members whose
+	// current location is location, excluding removed ones.
+	GetSpecimensAt(location string, req domain.PageRequest) ([]*CustodyEvent, int, error)
+
+	// SaveCurve stores the load curve of a member, replacing any previous one.
+	SaveCurve(c *Curve) error
+	// GetCurve wraps domain.ErrNotFound when the member has no curve.
+	GetCurve(memberID int) (*Curve, error)
+	GetCurves(memberIDs []int) (map[int]*Curve, error)
+
+	// GetWorkload counts the fractured members matching filter, whose
+	// defaults are already applied, by period, operative, specimen type and
+	// project.
+	GetWorkload(filter WorkloadFilter) ([]WorkloadRow, error)
+	// GetDueCounts counts the pending members by due day and specimen type,
+	// up to and including until. Overdue and unscheduled members are
+	// included.
+	GetDueCounts(projectID int, until time.Time) ([]DueCount, error)
+}
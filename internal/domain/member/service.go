@@ -1,13 +1,181 @@
 package member
 
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/audit"
+)
+
+// ResultWatcher is told about every result once it is stored, e.g. to raise
+// alerts on low strengths. Like audit.Recorder, it cannot fail the request.
+type ResultWatcher interface {
+	ResultRecorded(ctx context.Context, m *Member)
+}
+
 type Service struct {
-	repo Repository
+	repo    Repository
+	audit   audit.Recorder
+	results ResultWatcher
+}
+
+func NewMemberService(repo Repository, recorder audit.Recorder, watcher ResultWatcher) *Service {
+	return &Service{repo: repo, audit: recorder, results: watcher}
 }
 
-func NewMemberService(repo Repository) *Service {
-	return &Service{repo: repo}
+func (s *Service) SaveMembers(ctx context.Context, members []*Member) ([]*Member, error) {
+	if err := s.validate(members); err != nil {
+		return nil, err
+	}
+	saved, err := s.repo.SaveMembers(members)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range saved {
+		if err := s.audit.Record(ctx, audit.EntityMember, m.ID, audit.ActionCreate, nil, m); err != nil {
+			return nil, err
+		}
+		if m.Result != nil {
+			s.results.ResultRecorded(ctx, m)
+		}
+	}
+	return saved, nil
 }
 
-func (s *Service) SaveMembers(members []*Member) ([]*Member, error) {
-	return s.repo.SaveMembers(members)
-}
\ No newline at end of file
+func (s *Service) validate(members []*Member) error {
+	v := &domain.Validator{}
+	v.Check(len(members) > 0, "members", "at least one member is required")
+
+	familyIDs := make([]int, 0, len(members))
+	for i, m := range members {
+		prefix := fmt.Sprintf("members[%d].", i)
+		if m == nil {
+			v.Check(false, fmt.Sprintf("members[%d]", i), "is required")
+			continue
+		}
+		v.RequiredID(prefix+"family_id", m.FamilyID)
+		if m.FamilyID > 0 {
+			familyIDs = append(familyIDs, m.FamilyID)
+		}
+		if m.Result != nil {
+			v.NonNegative(prefix+"result", *m.Result)
+		}
+		if m.FractureDays != nil {
+			v.Check(*m.FractureDays > 0, prefix+"fracture_days", "must be greater than zero")
+		}
+		if m.FracturedAt != nil {
+			v.Check(m.Result != nil, prefix+"result", "is required once the member is fractured")
+		}
+		if m.FractureType != nil {
+			_, ok := FractureTypeByCode(*m.FractureType)
+			v.Check(ok, prefix+"fracture_type", "is not a known fracture type")
+		}
+		validateMeasurements(v, prefix, m)
+		if m.CappingMethod != nil {
+			v.Check(ValidCappingMethod(*m.CappingMethod), prefix+"capping_method", "must be sulfur, neoprene, ground or none")
+		}
+	}
+
+	for i, m := range members {
+		if m == nil {
+			continue
+		}
+		field := fmt.Sprintf("members[%d].machine_id", i)
+		if m.MachineID == nil {
+			v.Check(m.Result == nil, field, "is required once a result is set")
+			continue
+		}
+		v.Check(m.Result != nil, field, "requires a result")
+		inService, calibrated, err := s.repo.MachineCalibration(*m.MachineID, fractureDay(m))
+		if err != nil {
+			return err
+		}
+		v.Check(inService, field, "machine does not exist or is retired")
+		if inService {
+			v.Check(calibrated, field, "machine is out of calibration on the fracture date")
+		}
+	}
+
+	if len(familyIDs) > 0 {
+		shapes, err := s.repo.FamilyShapes(familyIDs)
+		if err != nil {
+			return err
+		}
+		for i, m := range members {
+			if m == nil || m.FamilyID <= 0 {
+				continue
+			}
+			shape, ok := shapes[m.FamilyID]
+			v.Check(ok, fmt.Sprintf("members[%d].family_id", i), "family does not exist")
+			if ok {
+				validateCoreLength(v, fmt.Sprintf("members[%d].", i), m, shape)
+			}
+		}
+	}
+
+	return v.Err()
+}
+
+func (s *Service) GetMembers(familyID int, req domain.PageRequest) (*domain.Page[*Member], error) {
+	if err := req.Prepare(); err != nil {
+		return nil, err
+	}
+	members, total, err := s.repo.GetMembers(familyID, req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(members, total, req, func(m *Member) int { return m.ID }), nil
+}
+
+func validateMeasurements(v *domain.Validator, prefix string, m *Member) {
+	for _, measurement := range []struct {
+		field string
+		value *float64
+	}{
+		{"diameter_1_cm", m.Diameter1CM},
+		{"diameter_2_cm", m.Diameter2CM},
+		{"height_cm", m.HeightCM},
+		{"mass_kg", m.MassKG},
+	} {
+		if measurement.value != nil {
+			v.Positive(prefix+measurement.field, *measurement.value)
+		}
+	}
+	if m.Diameter1CM != nil && m.Diameter2CM != nil && *m.Diameter1CM > 0 && *m.Diameter2CM > 0 {
+		d1, d2 := *m.Diameter1CM, *m.Diameter2CM
+		v.Check(math.Abs(d1-d2) <= MaxDiameterDifference*math.Max(d1, d2),
+			prefix+"diameter_2_cm", "differs from diameter_1_cm by more than 2%")
+	}
+}
+
+// validateCoreLength rejects cores shorter than their diameter, which cannot
+// be corrected (ASTM C42). Measured dimensions win over the family nominal.
+func validateCoreLength(v *domain.Validator, prefix string, m *Member, shape FamilyShape) {
+	if shape.SpecimenType != SpecimenCore || (m.HeightCM == nil && m.Diameter1CM == nil && m.Diameter2CM == nil) {
+		return
+	}
+	height, diameter := shape.Height, 2*shape.Radius
+	if m.HeightCM != nil {
+		height = *m.HeightCM
+	}
+	if d, ok := m.MeasuredDiameter(); ok {
+		diameter = d
+	}
+	if height > 0 && diameter > 0 {
+		v.Check(height >= diameter, prefix+"height_cm", "core length must be at least its diameter")
+	}
+}
+
+// fractureDay is the day the member was broken, today when not recorded.
+func fractureDay(m *Member) time.Time {
+	switch {
+	case m.FracturedAt != nil:
+		return *m.FracturedAt
+	case m.DateOfFracture != nil:
+		return *m.DateOfFracture
+	}
+	return time.Now()
+}
@@ -0,0 +1,249 @@
+package member
+
+import (
+	"context"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/user"
+)
+
+// Periods fractured members can be aggregated by. Weeks start on Monday and
+// are named after it.
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// defaultWorkloadDays is the range analyzed when no start date is given.
+const defaultWorkloadDays = 84
+
+// Forecast horizon of the backlog, in days.
+const (
+	defaultBacklogDays = 14
+	maxBacklogDays     = 90
+)
+
+// WorkloadFilter selects the fractured members counted in a workload
+// report. Zero fields do not filter.
+type WorkloadFilter struct {
+	// From and To bound the fracture day, inclusive. To defaults to today
+	// and From to 12 weeks before it.
+	From         *time.Time
+	To           *time.Time
+	Period       string
+	OperativeID  int
+	ProjectID    int
+	SpecimenType string
+}
+
+func (f *WorkloadFilter) normalize() {
+	if f.To == nil {
+		today := currentDay()
+		f.To = &today
+	}
+	if f.From == nil {
+		from := f.To.AddDate(0, 0, -defaultWorkloadDays+1)
+		f.From = &from
+	}
+	if f.Period == "" {
+		f.Period = PeriodWeek
+	}
+}
+
+func (f WorkloadFilter) validate() error {
+	v := &domain.Validator{}
+	v.Check(f.Period == PeriodDay || f.Period == PeriodWeek || f.Period == PeriodMonth,
+		"period", "must be day, week or month")
+	v.Check(f.OperativeID >= 0, "operative_id", "must not be negative")
+	v.Check(f.ProjectID >= 0, "project_id", "must not be negative")
+	v.Check(!f.To.Before(*f.From), "to", "must not be before from")
+	return v.Err()
+}
+
+// WorkloadRow counts the members an operative broke in a period, for one
+// specimen type and project.
+type WorkloadRow struct {
+	// Period is the day, the Monday of the week, or the month (YYYY-MM).
+	Period       string `db:"period" json:"period"`
+	OperativeID  *int   `db:"operative_id" json:"operative_id"`
+	Operative    string `db:"operative" json:"operative"`
+	SpecimenType string `db:"specimen_type" json:"specimen_type"`
+	ProjectID    int    `db:"project_id" json:"project_id"`
+	ProjectName  string `db:"project_name" json:"project_name"`
+	Count        int    `db:"count" json:"count"`
+}
+
+// OperativeWorkload totals the members broken by an operative.
+type OperativeWorkload struct {
+	OperativeID *int   `json:"operative_id"`
+	Operative   string `json:"operative"`
+	Count       int    `json:"count"`
+	// ActivePeriods counts the periods the operative broke anything in.
+	ActivePeriods int `json:"active_periods"`
+	// AveragePerPeriod is Count over every period of the range.
+	AveragePerPeriod float64 `json:"average_per_period"`
+}
+
+// Workload is the lab productivity over a date range.
+type Workload struct {
+	Period      string              `json:"period"`
+	From        domain.Date         `json:"from"`
+	To          domain.Date         `json:"to"`
+	Total       int                 `json:"total"`
+	Operatives  []OperativeWorkload `json:"operatives"`
+	Rows        []WorkloadRow       `json:"rows"`
+	PeriodCount int                 `json:"period_count"`
+}
+
+// GetWorkload aggregates the members fractured in the filter's range by
+// period, operative, specimen type and project. It names operatives, so only
+// admins and lab managers may see it.
+func (s *Service) GetWorkload(ctx context.Context, filter WorkloadFilter) (*Workload, error) {
+	if _, err := user.RequireRole(ctx, user.RoleAdmin, user.RoleLabManager); err != nil {
+		return nil, err
+	}
+	filter.normalize()
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.GetWorkload(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Workload{
+		Period:      filter.Period,
+		From:        domain.Date{Time: *filter.From},
+		To:          domain.Date{Time: *filter.To},
+		Rows:        rows,
+		Operatives:  []OperativeWorkload{},
+		PeriodCount: periodCount(*filter.From, *filter.To, filter.Period),
+	}
+	if w.Rows == nil {
+		w.Rows = []WorkloadRow{}
+	}
+
+	byOperative := map[int]int{}
+	periods := map[int]map[string]bool{}
+	for _, row := range rows {
+		w.Total += row.Count
+		// Results recorded without an operative are kept together under 0.
+		key := 0
+		if row.OperativeID != nil {
+			key = *row.OperativeID
+		}
+		i, ok := byOperative[key]
+		if !ok {
+			i = len(w.Operatives)
+			byOperative[key] = i
+			periods[key] = map[string]bool{}
+			w.Operatives = append(w.Operatives, OperativeWorkload{OperativeID: row.OperativeID, Operative: row.Operative})
+		}
+		w.Operatives[i].Count += row.Count
+		periods[key][row.Period] = true
+	}
+	for key, i := range byOperative {
+		w.Operatives[i].ActivePeriods = len(periods[key])
+		w.Operatives[i].AveragePerPeriod = float64(w.Operatives[i].Count) / float64(w.PeriodCount)
+	}
+	return w, nil
+}
+
+// currentDay is the local date at UTC midnight, like the dates parsed from
+// query strings.
+func currentDay() time.Time {
+	y, m, d := time.Now().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// periodCount is the number of days, weeks or months touched by the range.
+func periodCount(from, to time.Time, period string) int {
+	switch period {
+	case PeriodDay:
+		return int(to.Sub(from).Hours()/24) + 1
+	case PeriodMonth:
+		return (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	default:
+		monday := func(t time.Time) time.Time {
+			return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+		}
+		return int(monday(to).Sub(monday(from)).Hours()/24)/7 + 1
+	}
+}
+
+// BacklogFilter selects the pending members of a backlog forecast.
+type BacklogFilter struct {
+	// Days is the forecast horizon, from today.
+	Days      int
+	ProjectID int
+}
+
+// DueCount is how many pending members of a specimen type are due on a day.
+// Day is nil for members that cannot be scheduled: they have neither a
+// planned fracture date nor an age.
+type DueCount struct {
+	Day          *string `db:"day"`
+	SpecimenType string  `db:"specimen_type"`
+	Count        int     `db:"count"`
+}
+
+// BacklogDay is the number of members due for fracture on a day.
+type BacklogDay struct {
+	Date           domain.Date    `json:"date"`
+	Count          int            `json:"count"`
+	BySpecimenType map[string]int `json:"by_specimen_type"`
+}
+
+// Backlog forecasts the fractures coming up.
+type Backlog struct {
+	Today domain.Date `json:"today"`
+	// Overdue members were due before today and are still pending.
+	Overdue     int          `json:"overdue"`
+	Unscheduled int          `json:"unscheduled"`
+	Days        []BacklogDay `json:"days"`
+}
+
+// GetBacklog counts the pending members due for fracture on each of the
+// next filter.Days days, starting today.
+func (s *Service) GetBacklog(filter BacklogFilter) (*Backlog, error) {
+	if filter.Days == 0 {
+		filter.Days = defaultBacklogDays
+	}
+	v := &domain.Validator{}
+	v.Check(filter.Days > 0 && filter.Days <= maxBacklogDays, "days", "must be between 1 and 90")
+	v.Check(filter.ProjectID >= 0, "project_id", "must not be negative")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	today := currentDay()
+	until := today.AddDate(0, 0, filter.Days-1)
+	counts, err := s.repo.GetDueCounts(filter.ProjectID, until)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Backlog{Today: domain.Date{Time: today}, Days: make([]BacklogDay, filter.Days)}
+	index := map[string]int{}
+	for i := range b.Days {
+		day := today.AddDate(0, 0, i)
+		b.Days[i] = BacklogDay{Date: domain.Date{Time: day}, BySpecimenType: map[string]int{}}
+		index[day.Format("2006-01-02")] = i
+	}
+	for _, c := range counts {
+		switch {
+		case c.Day == nil:
+			b.Unscheduled += c.Count
+		case *c.Day < today.Format("2006-01-02"):
+			b.Overdue += c.Count
+		default:
+			if i, ok := index[*c.Day]; ok {
+				b.Days[i].Count += c.Count
+				b.Days[i].BySpecimenType[c.SpecimenType] += c.Count
+			}
+		}
+	}
+	return b, nil
+}
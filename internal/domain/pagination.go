@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"encoding/base64"
+	"strconv"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// PageRequest describes which slice of a list the caller wants. Lists can be
+// walked either by page number (offset) or, for large tables, by the opaque
+// cursor returned in the previous Page (keyset on the id column).
+type PageRequest struct {
+	Page     int
+	PageSize int
+	Cursor   string
+}
+
+// Normalize fills defaults for values the caller left out.
+func (p *PageRequest) Normalize() {
+	if p.Page == 0 {
+		p.Page = 1
+	}
+	if p.PageSize == 0 {
+		p.PageSize = DefaultPageSize
+	}
+}
+
+// Validate adds an error to v for every invalid pagination value.
+func (p PageRequest) Validate(v *Validator) {
+	v.Check(p.Page >= 1, "page", "must be greater than zero")
+	v.Check(p.PageSize >= 1 && p.PageSize <= MaxPageSize, "page_size", "must be between 1 and 100")
+	if p.Cursor != "" {
+		id, err := decodeCursor(p.Cursor)
+		v.Check(err == nil && id > 0, "cursor", "is not valid")
+	}
+}
+
+// Prepare normalizes the request and returns a *ValidationError when it is
+// not valid. Services call it for lists without extra filters.
+func (p *PageRequest) Prepare() error {
+	p.Normalize()
+	v := &Validator{}
+	p.Validate(v)
+	return v.Err()
+}
+
+// Offset is the number of rows to skip. It is zero in cursor mode, where the
+// repository filters on AfterID instead.
+func (p PageRequest) Offset() int {
+	if p.Cursor != "" {
+		return 0
+	}
+	return (p.Page - 1) * p.PageSize
+}
+
+// Limit is the number of rows repositories should fetch: one more than the
+// page size, so NewPage can tell whether there is a next page.
+func (p PageRequest) Limit() int {
+	return p.PageSize + 1
+}
+
+// AfterID is the id the keyset condition starts from, or zero without cursor.
+func (p PageRequest) AfterID() int {
+	id, _ := decodeCursor(p.Cursor)
+	return id
+}
+
+// Page is the envelope every list endpoint returns.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int    `json:"total"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// NewPage builds the envelope from up to req.Limit() rows. The extra row, if
+// present, is dropped and only used to emit the cursor for the next page.
+func NewPage[T any](items []T, total int, req PageRequest, id func(T) int) *Page[T] {
+	page := &Page[T]{
+		Items:    items,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if len(page.Items) > req.PageSize {
+		page.Items = page.Items[:req.PageSize]
+		page.HasMore = true
+		page.NextCursor = encodeCursor(id(page.Items[len(page.Items)-1]))
+	}
+	return page
+}
+
+func encodeCursor(id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(id)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(raw))
+}
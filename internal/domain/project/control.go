@@ -0,0 +1,265 @@
+package project
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+)
+
+// Groupings accepted by ControlFilter.GroupBy.
+const (
+	ControlBySupplier = "supplier"
+	ControlByMix      = "mix_design_code"
+)
+
+// Charts a ControlViolation can be found on.
+const (
+	ChartIndividuals = "individuals"
+	ChartMovingRange = "moving_range"
+)
+
+// Western Electric rules, checked on the individuals chart. Only the first
+// one applies to the moving range chart.
+const (
+	// RuleBeyondLimits is one point beyond 3 sigma.
+	RuleBeyondLimits = 1
+	// RuleTwoOfThree is 2 out of 3 consecutive points beyond 2 sigma on the
+	// same side.
+	RuleTwoOfThree = 2
+	// RuleFourOfFive is 4 out of 5 consecutive points beyond 1 sigma on the
+	// same side.
+	RuleFourOfFive = 3
+	// RuleEightInARow is 8 consecutive points on the same side of the
+	// center line.
+	RuleEightInARow = 4
+)
+
+var ruleDescriptions = map[int]string{
+	RuleBeyondLimits: "point beyond the 3 sigma control limits",
+	RuleTwoOfThree:   "2 of 3 consecutive points beyond 2 sigma on the same side",
+	RuleFourOfFive:   "4 of 5 consecutive points beyond 1 sigma on the same side",
+	RuleEightInARow:  "8 consecutive points on the same side of the center line",
+}
+
+// Shewhart constants for moving ranges of 2 consecutive points.
+const (
+	// d2 estimates sigma as the average moving range over d2.
+	d2 = 1.128
+	// d4 gives the upper limit of the moving range chart.
+	d4 = 3.267
+)
+
+// ControlFilter selects the strength tests of the control charts and how
+// they are grouped. Filtering by supplier or mix design code narrows the
+// charts to a single group.
+type ControlFilter struct {
+	QualityFilter
+	GroupBy string
+}
+
+func (f *ControlFilter) normalize() {
+	f.QualityFilter.normalize()
+	if f.GroupBy == "" {
+		f.GroupBy = ControlByMix
+	}
+}
+
+func (f ControlFilter) validate() error {
+	if err := f.QualityFilter.validate(); err != nil {
+		return err
+	}
+	v := &domain.Validator{}
+	v.Check(f.GroupBy == ControlBySupplier || f.GroupBy == ControlByMix,
+		"group_by", "must be supplier or mix_design_code")
+	return v.Err()
+}
+
+// ControlChart is an individuals (X̄) and moving range chart of the strength
+// tests of a supplier or mix design, in date of entry order. Each value is
+// the average strength in PSI of the specimens of a family broken at the
+// test age.
+type ControlChart struct {
+	GroupBy string         `json:"group_by"`
+	Group   string         `json:"group"`
+	AgeDays int            `json:"age_days"`
+	Points  []ControlPoint `json:"points"`
+	// Individuals and MovingRange are nil with fewer than 2 tests.
+	Individuals *ControlLimits     `json:"individuals"`
+	MovingRange *ControlLimits     `json:"moving_range"`
+	Violations  []ControlViolation `json:"violations"`
+}
+
+type ControlPoint struct {
+	FamilyID    int       `json:"family_id"`
+	ProjectID   int       `json:"project_id"`
+	Date        time.Time `json:"date"`
+	Value       float64   `json:"value"`
+	Specimens   int       `json:"specimens"`
+	MovingRange *float64  `json:"moving_range"`
+}
+
+type ControlLimits struct {
+	Center float64 `json:"center"`
+	Upper  float64 `json:"upper"`
+	Lower  float64 `json:"lower"`
+	// Sigma is the estimated process standard deviation, on the individuals
+	// chart only.
+	Sigma float64 `json:"sigma,omitempty"`
+}
+
+// ControlViolation flags the point at Index where a rule was met.
+type ControlViolation struct {
+	Chart       string `json:"chart"`
+	Rule        int    `json:"rule"`
+	Index       int    `json:"index"`
+	FamilyID    int    `json:"family_id"`
+	Description string `json:"description"`
+}
+
+// GetControlCharts builds a control chart per supplier or mix design from
+// the historic results matching filter, ordered by group.
+func (s *Service) GetControlCharts(filter ControlFilter) ([]*ControlChart, error) {
+	filter.normalize()
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+	families, err := s.repo.QualityFamilies(filter.QualityFilter)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(families, func(i, j int) bool {
+		return families[i].DateOfEntry.Before(families[j].DateOfEntry)
+	})
+
+	charts := map[string]*ControlChart{}
+	var groups []string
+	for _, f := range families {
+		strength, count := strengthTest(f, filter.AgeDays)
+		if count == 0 {
+			continue
+		}
+		group := f.MixDesignCode
+		if filter.GroupBy == ControlBySupplier {
+			group = f.Supplier
+		}
+		chart, ok := charts[group]
+		if !ok {
+			chart = &ControlChart{GroupBy: filter.GroupBy, Group: group, AgeDays: filter.AgeDays, Violations: []ControlViolation{}}
+			charts[group] = chart
+			groups = append(groups, group)
+		}
+		chart.Points = append(chart.Points, ControlPoint{
+			FamilyID:  f.ID,
+			ProjectID: f.ProjectID,
+			Date:      f.DateOfEntry,
+			Value:     strength,
+			Specimens: count,
+		})
+	}
+
+	sort.Strings(groups)
+	result := make([]*ControlChart, 0, len(groups))
+	for _, group := range groups {
+		chart := charts[group]
+		chart.computeLimits()
+		result = append(result, chart)
+	}
+	return result, nil
+}
+
+func (c *ControlChart) computeLimits() {
+	if len(c.Points) < 2 {
+		return
+	}
+
+	var sum, rangeSum float64
+	for i := range c.Points {
+		sum += c.Points[i].Value
+		if i > 0 {
+			mr := math.Abs(c.Points[i].Value - c.Points[i-1].Value)
+			c.Points[i].MovingRange = &mr
+			rangeSum += mr
+		}
+	}
+	center := sum / float64(len(c.Points))
+	averageRange := rangeSum / float64(len(c.Points)-1)
+	sigma := averageRange / d2
+
+	c.Individuals = &ControlLimits{Center: center, Upper: center + 3*sigma, Lower: center - 3*sigma, Sigma: sigma}
+	c.MovingRange = &ControlLimits{Center: averageRange, Upper: d4 * averageRange, Lower: 0}
+
+	values := make([]float64, len(c.Points))
+	for i, p := range c.Points {
+		values[i] = p.Value
+	}
+	for i, rules := range westernElectric(values, center, sigma) {
+		for _, rule := range rules {
+			c.addViolation(ChartIndividuals, rule, i)
+		}
+	}
+	for i, p := range c.Points {
+		if p.MovingRange != nil && *p.MovingRange > c.MovingRange.Upper {
+			c.addViolation(ChartMovingRange, RuleBeyondLimits, i)
+		}
+	}
+}
+
+func (c *ControlChart) addViolation(chart string, rule, index int) {
+	description := ruleDescriptions[rule]
+	if chart == ChartMovingRange {
+		description = "moving range above the upper control limit"
+	}
+	c.Violations = append(c.Violations, ControlViolation{
+		Chart:       chart,
+		Rule:        rule,
+		Index:       index,
+		FamilyID:    c.Points[index].FamilyID,
+		Description: description,
+	})
+}
+
+// westernElectric returns, for each value, the rules met by the run ending
+// at it. A rule is only reported on points that take part in it.
+func westernElectric(values []float64, center, sigma float64) [][]int {
+	// zone is how many sigmas a value is from the center, signed.
+	zone := func(i int) float64 {
+		if sigma == 0 {
+			return 0
+		}
+		return (values[i] - center) / sigma
+	}
+	// beyond counts the values in [from, to] beyond limit sigmas on side.
+	beyond := func(from, to int, limit, side float64) int {
+		n := 0
+		for i := from; i <= to; i++ {
+			if zone(i)*side > limit {
+				n++
+			}
+		}
+		return n
+	}
+
+	rules := make([][]int, len(values))
+	for i := range values {
+		z := zone(i)
+		side := 1.0
+		if z < 0 {
+			side = -1
+		}
+		if math.Abs(z) > 3 {
+			rules[i] = append(rules[i], RuleBeyondLimits)
+		}
+		if i >= 2 && math.Abs(z) > 2 && beyond(i-2, i, 2, side) >= 2 {
+			rules[i] = append(rules[i], RuleTwoOfThree)
+		}
+		if i >= 4 && math.Abs(z) > 1 && beyond(i-4, i, 1, side) >= 4 {
+			rules[i] = append(rules[i], RuleFourOfFive)
+		}
+		if i >= 7 && values[i] != center && beyond(i-7, i, 0, side) == 8 {
+			rules[i] = append(rules[i], RuleEightInARow)
+		}
+	}
+	return rules
+}
@@ -2,14 +2,34 @@
 package project
 
 import (
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/client"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/family"
 )
 
+// Lifecycle values of Project.Status.
+const (
+	StatusActive   = "active"
+	StatusClosed   = "closed"
+	StatusArchived = "archived"
+)
+
 type Project struct {
-	ID       int             `db:"id" json:"id"`
-	Name     string          `db:"name" json:"name"`
-	ClientID int             `db:"client_id" json:"client_id"`
-	Client   client.Client   `db:"-" json:"client"`
-	Families []family.Family `db:"-" json:"families"`
+	ID       int    `db:"id" json:"id"`
+	Name     string `db:"name" json:"name"`
+	ClientID int    `db:"client_id" json:"client_id"`
+	// Address and City locate the construction site; Latitude and
+	// Longitude are optional GPS coordinates in decimal degrees.
+	Address             string          `db:"address" json:"address"`
+	City                string          `db:"city" json:"city"`
+	Latitude            *float64        `db:"latitude" json:"latitude"`
+	Longitude           *float64        `db:"longitude" json:"longitude"`
+	Contractor          string          `db:"contractor" json:"contractor"`
+	SupervisingEngineer string          `db:"supervising_engineer" json:"supervising_engineer"`
+	ContractNumber      string          `db:"contract_number" json:"contract_number"`
+	StartDate           domain.Date     `db:"start_date" json:"start_date"`
+	EndDate             domain.Date     `db:"end_date" json:"end_date"`
+	Status              string          `db:"status" json:"status"`
+	Client              client.Client   `db:"-" json:"client"`
+	Families            []family.Family `db:"-" json:"families"`
 }
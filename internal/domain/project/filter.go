@@ -0,0 +1,80 @@
+package project
+
+import (
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+)
+
+// Status values accepted by ListFilter.Status. They describe the reporting
+// progress and are unrelated to the lifecycle kept in Project.Status.
+const (
+	StatusPending  = "pending"
+	StatusReported = "reported"
+)
+
+// Sort values accepted by ListFilter.Sort. A leading "-" reverses the order.
+const (
+	SortName    = "name"
+	SortCreated = "created"
+)
+
+// ListFilter holds the criteria used to list projects.
+type ListFilter struct {
+	// Search matches against the project name, the client name and the
+	// contract number.
+	Search   string
+	ClientID int
+	// ProjectStatus keeps projects in one lifecycle status (StatusActive...).
+	ProjectStatus string
+	// Contractor matches part of the contractor name.
+	Contractor string
+	// EntryFrom and EntryTo restrict to projects having at least one family
+	// whose date of entry falls in the (inclusive) range.
+	EntryFrom *time.Time
+	EntryTo   *time.Time
+	// Status is StatusPending for projects with members not reported yet and
+	// StatusReported for projects whose members are all reported.
+	Status string
+	Sort   string
+	domain.PageRequest
+}
+
+// Descending reports whether the sort was requested in reverse order.
+func (f ListFilter) Descending() bool {
+	return len(f.Sort) > 0 && f.Sort[0] == '-'
+}
+
+// SortField returns the sort key without its direction prefix.
+func (f ListFilter) SortField() string {
+	if f.Descending() {
+		return f.Sort[1:]
+	}
+	return f.Sort
+}
+
+func (f *ListFilter) normalize() {
+	f.PageRequest.Normalize()
+	if f.Sort == "" {
+		f.Sort = SortCreated
+	}
+}
+
+func (f ListFilter) validate() error {
+	v := &domain.Validator{}
+	f.PageRequest.Validate(v)
+	v.Check(f.ClientID >= 0, "client_id", "must not be negative")
+	v.Check(f.Status == "" || f.Status == StatusPending || f.Status == StatusReported,
+		"status", "must be pending or reported")
+	v.Check(f.ProjectStatus == "" || validStatus(f.ProjectStatus),
+		"project_status", "must be active, closed or archived")
+	v.Check(f.SortField() == SortName || f.SortField() == SortCreated,
+		"sort", "must be name or created, optionally prefixed with -")
+	// Keyset pagination walks the id column, so it only works when the
+	// list is ordered by it.
+	v.Check(f.Cursor == "" || f.SortField() == SortCreated, "cursor", "requires sort=created")
+	if f.EntryFrom != nil && f.EntryTo != nil {
+		v.Check(!f.EntryTo.Before(*f.EntryFrom), "entry_to", "must not be before entry_from")
+	}
+	return v.Err()
+}
@@ -0,0 +1,43 @@
+package project
+
+import "github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
+
+// FractureTypeStats breaks down the fractured members of a project by
+// failure pattern.
+type FractureTypeStats struct {
+	FracturedCount int `json:"fractured_count"`
+	// UnclassifiedCount counts members without a fracture type, or with a
+	// legacy value that is not in the catalogue.
+	UnclassifiedCount int                `json:"unclassified_count"`
+	Types             []FractureTypeStat `json:"types"`
+}
+
+type FractureTypeStat struct {
+	member.FractureType
+	Count int `json:"count"`
+	// Percent is over every fractured member of the project.
+	Percent float64 `json:"percent"`
+}
+
+func (s *Service) GetFractureTypeStats(projectID int) (*FractureTypeStats, error) {
+	counts, err := s.repo.FractureTypeCounts(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &FractureTypeStats{Types: make([]FractureTypeStat, 0, len(member.FractureTypes))}
+	for code, count := range counts {
+		stats.FracturedCount += count
+		if _, ok := member.FractureTypeByCode(code); !ok {
+			stats.UnclassifiedCount += count
+		}
+	}
+	for _, t := range member.FractureTypes {
+		stat := FractureTypeStat{FractureType: t, Count: counts[t.Code]}
+		if stats.FracturedCount > 0 {
+			stat.Percent = float64(stat.Count) / float64(stats.FracturedCount) * 100
+		}
+		stats.Types = append(stats.Types, stat)
+	}
+	return stats, nil
+}
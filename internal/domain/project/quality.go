@@ -0,0 +1,245 @@
+package project
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/family"
+)
+
+// Basis of the required average strength of a QualityGroup.
+const (
+	// RequiredFromStdDev uses the standard deviation of 15 or more tests.
+	RequiredFromStdDev = "std_dev"
+	// RequiredFromTable uses the fixed margins for fewer tests.
+	RequiredFromTable = "table"
+)
+
+// minStdDevTests is the fewest tests whose standard deviation may be used
+// to work out f'cr (ACI 318 26.4.3.1).
+const minStdDevTests = 15
+
+// QualityFilter selects the results a quality analysis runs over. Zero
+// fields do not filter.
+type QualityFilter struct {
+	ClientID  int
+	ProjectID int
+	// DesignResistance is f'c in PSI.
+	DesignResistance float64
+	MixDesignCode    string
+	Supplier         string
+	// From and To bound the families' date of entry, inclusive.
+	From *time.Time
+	To   *time.Time
+	// AgeDays is the test age; only specimens broken at it count. Defaults
+	// to family.AcceptanceAgeDays.
+	AgeDays int
+}
+
+func (f *QualityFilter) normalize() {
+	if f.AgeDays == 0 {
+		f.AgeDays = family.AcceptanceAgeDays
+	}
+}
+
+func (f QualityFilter) validate() error {
+	v := &domain.Validator{}
+	v.Check(f.ClientID >= 0, "client_id", "must not be negative")
+	v.Check(f.ProjectID >= 0, "project_id", "must not be negative")
+	v.NonNegative("design_resistance", f.DesignResistance)
+	v.Positive("age_days", float64(f.AgeDays))
+	if f.From != nil && f.To != nil {
+		v.Check(!f.To.Before(*f.From), "to", "must not be before from")
+	}
+	return v.Err()
+}
+
+// QualityStats is the statistical quality control of the strength tests
+// matching a filter, per design strength and mix.
+type QualityStats struct {
+	AgeDays int            `json:"age_days"`
+	Groups  []QualityGroup `json:"groups"`
+}
+
+// QualityGroup summarizes the strength tests of one design strength and mix
+// design. A test is the average strength of the specimens of a family
+// broken at the test age. Strengths are in PSI.
+type QualityGroup struct {
+	DesignResistance float64 `json:"design_resistance"`
+	MixDesignCode    string  `json:"mix_design_code"`
+	TestCount        int     `json:"test_count"`
+	SpecimenCount    int     `json:"specimen_count"`
+	Mean             float64 `json:"mean"`
+	// StdDev is the sample standard deviation, nil with fewer than 2 tests.
+	StdDev *float64 `json:"std_dev"`
+	// CoefficientOfVariation is StdDev over Mean, in percent.
+	CoefficientOfVariation *float64 `json:"coefficient_of_variation"`
+	BelowDesignCount       int      `json:"below_design_count"`
+	BelowDesignPercent     float64  `json:"below_design_percent"`
+	// LowTestCount counts tests below f'c - 500 PSI, or 0.9 f'c above
+	// 5000 PSI: each of them fails acceptance on its own.
+	LowTestCount int `json:"low_test_count"`
+	// RequiredAverage is f'cr, the average strength the mix must be
+	// proportioned for given the variability observed.
+	RequiredAverage      float64 `json:"required_average"`
+	RequiredAverageBasis string  `json:"required_average_basis"`
+	MeetsRequiredAverage bool    `json:"meets_required_average"`
+}
+
+// GetQualityStats computes the quality control statistics of the historic
+// results matching filter.
+func (s *Service) GetQualityStats(filter QualityFilter) (*QualityStats, error) {
+	filter.normalize()
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+	families, err := s.repo.QualityFamilies(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	type groupKey struct {
+		design float64
+		mix    string
+	}
+	tests := map[groupKey][]float64{}
+	specimens := map[groupKey]int{}
+	for _, f := range families {
+		strength, count := strengthTest(f, filter.AgeDays)
+		if count == 0 {
+			continue
+		}
+		key := groupKey{f.DesignResistance, f.MixDesignCode}
+		tests[key] = append(tests[key], strength)
+		specimens[key] += count
+	}
+
+	stats := &QualityStats{AgeDays: filter.AgeDays, Groups: []QualityGroup{}}
+	for key, values := range tests {
+		group := qualityGroup(key.design, values)
+		group.MixDesignCode = key.mix
+		group.SpecimenCount = specimens[key]
+		stats.Groups = append(stats.Groups, group)
+	}
+	sort.Slice(stats.Groups, func(i, j int) bool {
+		a, b := stats.Groups[i], stats.Groups[j]
+		if a.DesignResistance != b.DesignResistance {
+			return a.DesignResistance < b.DesignResistance
+		}
+		return a.MixDesignCode < b.MixDesignCode
+	})
+	return stats, nil
+}
+
+// strengthTest returns the average strength in PSI of the specimens of f
+// broken at ageDays and how many there were. Beams are never counted: f'cr
+// and control limits only apply to compressive strength.
+func strengthTest(f family.Family, ageDays int) (float64, int) {
+	if f.Geometry().Flexural() {
+		return 0, 0
+	}
+	var sum float64
+	var count int
+	for _, m := range f.Members {
+		if m.Result == nil || m.FractureDays == nil || *m.FractureDays != ageDays {
+			continue
+		}
+		if !f.MemberGeometry(m).Valid() {
+			continue
+		}
+		sum += f.MemberStrengthPSI(m)
+		count++
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return sum / float64(count), count
+}
+
+func qualityGroup(design float64, tests []float64) QualityGroup {
+	g := QualityGroup{DesignResistance: design, TestCount: len(tests)}
+
+	var sum float64
+	for _, t := range tests {
+		sum += t
+		if t < design {
+			g.BelowDesignCount++
+		}
+		if t < lowTestLimit(design) {
+			g.LowTestCount++
+		}
+	}
+	g.Mean = sum / float64(len(tests))
+	g.BelowDesignPercent = float64(g.BelowDesignCount) / float64(len(tests)) * 100
+
+	if len(tests) >= 2 {
+		var squares float64
+		for _, t := range tests {
+			squares += (t - g.Mean) * (t - g.Mean)
+		}
+		stdDev := math.Sqrt(squares / float64(len(tests)-1))
+		g.StdDev = &stdDev
+		if g.Mean > 0 {
+			cv := stdDev / g.Mean * 100
+			g.CoefficientOfVariation = &cv
+		}
+	}
+
+	g.RequiredAverage, g.RequiredAverageBasis = RequiredAverageStrength(design, g.StdDev, len(tests))
+	g.MeetsRequiredAverage = g.Mean >= g.RequiredAverage
+	return g
+}
+
+// lowTestLimit is the strength under which a single test fails acceptance
+// (ACI 318 26.12.3.1).
+func lowTestLimit(design float64) float64 {
+	if design <= 5000 {
+		return design - 500
+	}
+	return 0.9 * design
+}
+
+// RequiredAverageStrength returns f'cr in PSI for a design strength f'c in
+// PSI following ACI 318 26.4.3.1: from the standard deviation of the tests
+// when there are at least 15, from fixed margins otherwise.
+func RequiredAverageStrength(design float64, stdDev *float64, tests int) (float64, string) {
+	if stdDev == nil || tests < minStdDevTests {
+		switch {
+		case design < 3000:
+			return design + 1000, RequiredFromTable
+		case design <= 5000:
+			return design + 1200, RequiredFromTable
+		default:
+			return 1.1*design + 700, RequiredFromTable
+		}
+	}
+
+	ks := stdDevModificationFactor(tests) * *stdDev
+	if design <= 5000 {
+		return math.Max(design+1.34*ks, design+2.33*ks-500), RequiredFromStdDev
+	}
+	return math.Max(design+1.34*ks, 0.9*design+2.33*ks), RequiredFromStdDev
+}
+
+// stdDevModificationFactor raises the standard deviation of fewer than 30
+// tests, interpolating between the values of ACI 318 Table 26.4.3.1(b).
+func stdDevModificationFactor(tests int) float64 {
+	steps := []struct {
+		tests  int
+		factor float64
+	}{{15, 1.16}, {20, 1.08}, {25, 1.03}, {30, 1.00}}
+
+	if tests >= 30 {
+		return 1
+	}
+	for i := 1; i < len(steps); i++ {
+		lo, hi := steps[i-1], steps[i]
+		if tests <= hi.tests {
+			frac := float64(tests-lo.tests) / float64(hi.tests-lo.tests)
+			return lo.factor + frac*(hi.factor-lo.factor)
+		}
+	}
+	return steps[0].factor
+}
@@ -1,8 +1,24 @@
 package project
 
+import "github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/family"
+
 type Repository interface {
-	GetProjects(page int) ([]*Project, error)
+	// GetProjects returns up to filter.Limit() projects and the total number
+	// of projects matching the filter.
+	GetProjects(filter ListFilter) ([]*Project, int, error)
+	// GetProjectSummaries is the aggregate counterpart of GetProjects.
+	GetProjectSummaries(filter ListFilter) ([]*Summary, int, error)
 	GetProjectByID(ID int) (*Project, error)
 	SaveProject(project *Project) (*Project, error)
-	GetProjectsByClientID(clientID int) ([]*Project, error)
+	// UpdateProject also moves the project's families to its client, in the
+	// same transaction.
+	UpdateProject(project *Project) error
+	ClientExists(clientID int) (bool, error)
+	// FractureTypeCounts counts the fractured members of the project by
+	// fracture_type, with "" for the ones without one. It wraps
+	// domain.ErrNotFound when the project does not exist.
+	FractureTypeCounts(projectID int) (map[string]int, error)
+	// QualityFamilies returns the families matching the filter with only
+	// their members fractured at filter.AgeDays loaded.
+	QualityFamilies(filter QualityFilter) ([]family.Family, error)
 }
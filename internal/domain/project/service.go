@@ -1,25 +1,128 @@
 package project
 
+import (
+	"context"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/audit"
+)
+
 type Service struct {
-	repo Repository
+	repo  Repository
+	audit audit.Recorder
 }
 
-func NewService(r Repository) *Service {
-	return &Service{repo: r}
+func NewService(r Repository, recorder audit.Recorder) *Service {
+	return &Service{repo: r, audit: recorder}
 }
 
 func (s *Service) GetProjectByID(ID int) (*Project, error) {
 	return s.repo.GetProjectByID(ID)
 }
 
-func (s *Service) GetProjects(page int) ([]*Project, error) {
-	return s.repo.GetProjects(page)
+func (s *Service) GetProjects(filter ListFilter) (*domain.Page[*Project], error) {
+	filter.normalize()
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+	projects, total, err := s.repo.GetProjects(filter)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(projects, total, filter.PageRequest, projectID), nil
+}
+
+func (s *Service) GetProjectSummaries(filter ListFilter) (*domain.Page[*Summary], error) {
+	filter.normalize()
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+	summaries, total, err := s.repo.GetProjectSummaries(filter)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(summaries, total, filter.PageRequest, func(s *Summary) int { return s.ID }), nil
+}
+
+func (s *Service) SaveProject(ctx context.Context, project *Project) (*Project, error) {
+	if err := s.validate(project); err != nil {
+		return nil, err
+	}
+	created, err := s.repo.SaveProject(project)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.Record(ctx, audit.EntityProject, created.ID, audit.ActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateProject replaces the data of an existing project, e.g. to close or
+// archive it.
+func (s *Service) UpdateProject(ctx context.Context, project *Project) (*Project, error) {
+	before, err := s.repo.GetProjectByID(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(project); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateProject(project); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.GetProjectByID(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.Record(ctx, audit.EntityProject, updated.ID, audit.ActionUpdate, before, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *Service) validate(project *Project) error {
+	if project.Status == "" {
+		project.Status = StatusActive
+	}
+
+	v := &domain.Validator{}
+	v.Required("name", project.Name)
+	v.MaxLength("name", project.Name, 255)
+	v.RequiredID("client_id", project.ClientID)
+	v.MaxLength("address", project.Address, 255)
+	v.MaxLength("city", project.City, 100)
+	v.MaxLength("contractor", project.Contractor, 255)
+	v.MaxLength("supervising_engineer", project.SupervisingEngineer, 255)
+	v.MaxLength("contract_number", project.ContractNumber, 100)
+	v.Check(validStatus(project.Status), "status", "must be active, closed or archived")
+	v.Check((project.Latitude == nil) == (project.Longitude == nil),
+		"latitude", "latitude and longitude go together")
+	if project.Latitude != nil {
+		v.Check(*project.Latitude >= -90 && *project.Latitude <= 90, "latitude", "must be between -90 and 90")
+	}
+	if project.Longitude != nil {
+		v.Check(*project.Longitude >= -180 && *project.Longitude <= 180, "longitude", "must be between -180 and 180")
+	}
+	if !project.StartDate.IsZero() && !project.EndDate.IsZero() {
+		v.Check(!project.EndDate.Before(project.StartDate.Time), "end_date", "must not be before start_date")
+	}
+
+	if !v.HasErrors("client_id") {
+		exists, err := s.repo.ClientExists(project.ClientID)
+		if err != nil {
+			return err
+		}
+		v.Check(exists, "client_id", "client does not exist")
+	}
+
+	return v.Err()
 }
 
-func (s *Service) SaveProject(project *Project) (*Project, error) {
-	return s.repo.SaveProject(project)
+func validStatus(status string) bool {
+	return status == StatusActive || status == StatusClosed || status == StatusArchived
 }
 
-func (s *Service) GetProjectsByClientID(clientID int) ([]*Project, error) {
-	return s.repo.GetProjectsByClientID(clientID)
+func projectID(p *Project) int {
+	return p.ID
 }
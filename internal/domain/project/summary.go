@@ -0,0 +1,29 @@
+package project
+
+import (
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/client"
+)
+
+// Summary is the lightweight view of a project used by list endpoints. The
+// counters are computed in the database instead of loading every member;
+// only members old enough to count towards compliance are loaded.
+type Summary struct {
+	ID             int           `db:"id" json:"id"`
+	Name           string        `db:"name" json:"name"`
+	ClientID       int           `db:"client_id" json:"client_id"`
+	Status         string        `db:"status" json:"status"`
+	Contractor     string        `db:"contractor" json:"contractor"`
+	ContractNumber string        `db:"contract_number" json:"contract_number"`
+	Client         client.Client `db:"-" json:"client"`
+	FamilyCount    int           `db:"-" json:"family_count"`
+	PendingCount   int           `db:"-" json:"pending_count"`
+	FracturedCount int           `db:"-" json:"fractured_count"`
+	ReportedCount  int           `db:"-" json:"reported_count"`
+	// LastActivity is the latest family entry or fracture date.
+	LastActivity domain.Date `db:"-" json:"last_activity"`
+	// Compliance is the percentage of specimens broken at
+	// family.AcceptanceAgeDays or later that reached the design resistance.
+	// Nil when there are none.
+	Compliance *float64 `db:"-" json:"compliance"`
+}
@@ -0,0 +1,44 @@
+// Package report tracks the reports handed out for a family.
+package report
+
+import "time"
+
+// Issued is a generated report. It is superseded once an approved result
+// correction changes the data it was built from.
+type Issued struct {
+	ID                       int         `db:"id" json:"id"`
+	ProjectID                int         `db:"project_id" json:"project_id"`
+	FamilyID                 int         `db:"family_id" json:"family_id"`
+	Filename                 string      `db:"filename" json:"filename"`
+	IssuedBy                 *int        `db:"issued_by" json:"issued_by"`
+	IssuedAt                 time.Time   `db:"issued_at" json:"issued_at"`
+	Superseded               bool        `db:"superseded" json:"superseded"`
+	SupersededAt             *time.Time  `db:"superseded_at" json:"superseded_at"`
+	SupersededByCorrectionID *int        `db:"superseded_by_correction_id" json:"superseded_by_correction_id"`
+	Recipients               []Recipient `db:"-" json:"recipients"`
+	// File is the PDF handed out. Reports issued before files were kept
+	// have none. It is only loaded by GetIssuedFile.
+	File []byte `db:"-" json:"-"`
+}
+
+// Delivery statuses of a Recipient.
+const (
+	DeliveryPending = "pending"
+	DeliverySent    = "sent"
+	DeliveryFailed  = "failed"
+)
+
+// Recipient is a client contact the report was addressed to. Name and
+// email are kept as they were when the report was issued, along with the
+// outcome of emailing it to them.
+type Recipient struct {
+	IssuedReportID int        `db:"issued_report_id" json:"-"`
+	ContactID      *int       `db:"contact_id" json:"contact_id"`
+	Name           string     `db:"name" json:"name"`
+	Email          string     `db:"email" json:"email"`
+	Status         string     `db:"status" json:"status"`
+	Attempts       int        `db:"attempts" json:"attempts"`
+	LastError      *string    `db:"last_error" json:"last_error"`
+	LastAttemptAt  *time.Time `db:"last_attempt_at" json:"last_attempt_at"`
+	SentAt         *time.Time `db:"sent_at" json:"sent_at"`
+}
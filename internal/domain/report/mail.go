@@ -0,0 +1,34 @@
+package report
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+)
+
+// ErrMailNotConfigured is returned when a report is to be emailed and no
+// Sender was set up.
+var ErrMailNotConfigured = fmt.Errorf("mail delivery is not configured: %w", domain.ErrUnavailable)
+
+// Attachment is a file sent along a Message.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// Message is an email to a single recipient.
+type Message struct {
+	ToName      string
+	To          string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+// Sender delivers email. The SMTP implementation lives in infra/mail; any
+// other transport only has to satisfy this interface.
+type Sender interface {
+	Send(ctx context.Context, m *Message) error
+}
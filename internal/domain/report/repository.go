@@ -0,0 +1,22 @@
+package report
+
+import (
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+)
+
+type Repository interface {
+	SaveIssued(i *Issued) error
+	GetIssued(familyID int, req domain.PageRequest) ([]*Issued, int, error)
+	// GetIssuedByID returns the report with its recipients but without its
+	// file. It wraps domain.ErrNotFound when it does not exist.
+	GetIssuedByID(ID int) (*Issued, error)
+	// GetIssuedFile returns nil when the report was issued before files
+	// were kept.
+	GetIssuedFile(ID int) ([]byte, error)
+	// RecordDelivery stores the outcome of emailing the report to the
+	// recipient with the given email: sent when sendErr is nil, failed
+	// otherwise.
+	RecordDelivery(ID int, email string, sendErr error, at time.Time) error
+}
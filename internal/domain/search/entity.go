@@ -0,0 +1,23 @@
+// Package search finds clients, projects and families by the names staff
+// remember them by.
+package search
+
+// Kinds of hit returned by a search.
+const (
+	KindClient  = "client"
+	KindProject = "project"
+	KindFamily  = "family"
+)
+
+// Hit is one search match. The ids point to the entity that matched and to
+// the project and client it belongs to, so the UI can link straight to it.
+type Hit struct {
+	Kind  string `db:"kind" json:"kind"`
+	ID    int    `db:"entity_id" json:"id"`
+	Title string `db:"title" json:"title"`
+	// Highlight is the title as escaped HTML, with the matched words
+	// wrapped in <mark> tags.
+	Highlight string `db:"highlight" json:"highlight"`
+	ProjectID *int   `db:"project_id" json:"project_id"`
+	ClientID  int    `db:"client_id" json:"client_id"`
+}
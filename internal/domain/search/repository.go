@@ -0,0 +1,5 @@
+package search
+
+type Repository interface {
+	Search(terms []string, limit int) ([]*Hit, error)
+}
@@ -0,0 +1,42 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 50
+)
+
+type Service struct {
+	repo Repository
+}
+
+func NewSearchService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Search matches every word of query as a prefix, so "puen abut" finds
+// "Puente — estribo abutment".
+func (s *Service) Search(query string, limit int) ([]*Hit, error) {
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+
+	terms := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	v := &domain.Validator{}
+	v.Check(len(terms) > 0, "q", "is required")
+	v.Check(limit >= 1 && limit <= MaxLimit, "limit", "must be between 1 and 50")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Search(terms, limit)
+}
@@ -0,0 +1,36 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+)
+
+type contextKey struct{}
+
+// WithUser returns a copy of ctx carrying the authenticated user.
+func WithUser(ctx context.Context, u *User) context.Context {
+	return context.WithValue(ctx, contextKey{}, u)
+}
+
+// FromContext returns the authenticated user stored in ctx, if any.
+func FromContext(ctx context.Context) (*User, bool) {
+	u, ok := ctx.Value(contextKey{}).(*User)
+	return u, ok && u != nil
+}
+
+// RequireRole returns the authenticated user when it has one of roles, or
+// an error wrapping domain.ErrUnauthorized or domain.ErrForbidden.
+func RequireRole(ctx context.Context, roles ...string) (*User, error) {
+	u, ok := FromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	for _, role := range roles {
+		if u.Role == role {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("role %q: %w", u.Role, domain.ErrForbidden)
+}
@@ -1,11 +1,27 @@
 package user
 
+// Roles a user can have. Only admins and lab managers use the backoffice;
+// operatives record results from the lab.
+const (
+	RoleAdmin      = "admin"
+	RoleLabManager = "lab_manager"
+	RoleOperative  = "operative"
+)
+
+func ValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleLabManager, RoleOperative:
+		return true
+	}
+	return false
+}
+
 type User struct {
 	ID        int    `db:"id" json:"id"`
 	FirstName string `db:"first_name" json:"firstName"`
 	LastName  string `db:"last_name" json:"lastName"`
 	Role      string `db:"role" json:"role"`
 	Username  string `db:"username" json:"username"`
-	Password  string `db:"password" json:"password"`
+	Password  string `db:"password" json:"-"`
 	IsActive  bool   `db:"is_active" json:"isActive"`
 }
@@ -1,5 +1,24 @@
 package user
 
+import (
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+)
+
 type Repository interface {
 	GetByUsername(username string) (*User, error)
+	GetUsers(req domain.PageRequest) ([]*User, int, error)
+	// GetByID wraps domain.ErrNotFound when the user does not exist.
+	GetByID(ID int) (*User, error)
+	// UsernameTaken reports whether another user than exceptID has username.
+	UsernameTaken(username string, exceptID int) (bool, error)
+	CreateUser(u *User) error
+	// UpdateUser stores the names, role and active flag of u, and its
+	// password hash when not empty.
+	UpdateUser(u *User) error
+	CreateSession(userID int, tokenHash string, expiresAt time.Time) error
+	// GetBySessionToken returns the active user owning a session that has not
+	// expired at now.
+	GetBySessionToken(tokenHash string, now time.Time) (*User, error)
 }
@@ -1,20 +1,43 @@
 package user
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
 	"golang.org/x/crypto/bcrypt"
 )
 
 var ErrInvalidCredentials = errors.New("invalid credentials")
 var ErrInvalidRole = errors.New("invalid role")
 
+// Recorder leaves the audit trail of user changes. audit.Service satisfies
+// it; the audit package depends on this one, so it cannot be imported here.
+type Recorder interface {
+	Record(ctx context.Context, entity string, entityID int, action string, before, after interface{}) error
+}
+
+// auditEntity is audit.EntityUser.
+const auditEntity = "user"
+
+// Audit actions, as in the audit package.
+const (
+	auditCreate = "create"
+	auditUpdate = "update"
+)
+
+// minPasswordLength is the shortest password accepted for a user.
+const minPasswordLength = 8
+
 type Service struct {
-	repo Repository
+	repo  Repository
+	audit Recorder
 }
 
-func NewService(r Repository) *Service {
-	return &Service{repo: r}
+func NewService(r Repository, recorder Recorder) *Service {
+	return &Service{repo: r, audit: recorder}
 }
 
 func (s *Service) Login(username string, password string) (User, error) {
@@ -24,7 +47,7 @@ func (s *Service) Login(username string, password string) (User, error) {
 		return User{}, ErrInvalidCredentials
 	}
 
-	if u.Role != "admin" {
+	if u.Role != RoleAdmin && u.Role != RoleLabManager {
 		return User{}, ErrInvalidCredentials
 	}
 
@@ -34,3 +57,128 @@ func (s *Service) Login(username string, password string) (User, error) {
 
 	return *u, nil
 }
+
+// GetUsers lists the users. Only admins and lab managers may read it.
+func (s *Service) GetUsers(ctx context.Context, req domain.PageRequest) (*domain.Page[*User], error) {
+	if _, err := RequireRole(ctx, RoleAdmin, RoleLabManager); err != nil {
+		return nil, err
+	}
+	if err := req.Prepare(); err != nil {
+		return nil, err
+	}
+	users, total, err := s.repo.GetUsers(req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPage(users, total, req, func(u *User) int { return u.ID }), nil
+}
+
+// CreateUser adds a user with the plain password in u.Password. Only admins
+// may manage users.
+func (s *Service) CreateUser(ctx context.Context, u *User) (*User, error) {
+	if _, err := RequireRole(ctx, RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	u.ID = 0
+	u.Username = strings.TrimSpace(u.Username)
+	v := &domain.Validator{}
+	v.Required("username", u.Username)
+	v.MaxLength("username", u.Username, 100)
+	validateUser(v, u)
+	v.Check(len(u.Password) >= minPasswordLength, "password", fmt.Sprintf("must have at least %d characters", minPasswordLength))
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.checkUsername(u); err != nil {
+		return nil, err
+	}
+
+	if err := s.hashPassword(u); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateUser(u); err != nil {
+		return nil, err
+	}
+	if err := s.audit.Record(ctx, auditEntity, u.ID, auditCreate, nil, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// UpdateUser replaces the names, role and active flag of a user, and its
+// password when u.Password is not empty. The username cannot change. Admins
+// cannot take away their own admin access.
+func (s *Service) UpdateUser(ctx context.Context, u *User) (*User, error) {
+	admin, err := RequireRole(ctx, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.repo.GetByID(u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	u.Username = before.Username
+	v := &domain.Validator{}
+	validateUser(v, u)
+	if u.Password != "" {
+		v.Check(len(u.Password) >= minPasswordLength, "password", fmt.Sprintf("must have at least %d characters", minPasswordLength))
+	}
+	if u.ID == admin.ID {
+		v.Check(u.Role == RoleAdmin, "role", "you cannot remove your own admin role")
+		v.Check(u.IsActive, "isActive", "you cannot deactivate yourself")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.hashPassword(u); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateUser(u); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.GetByID(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.Record(ctx, auditEntity, u.ID, auditUpdate, before, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func validateUser(v *domain.Validator, u *User) {
+	u.FirstName = strings.TrimSpace(u.FirstName)
+	u.LastName = strings.TrimSpace(u.LastName)
+	v.Required("firstName", u.FirstName)
+	v.MaxLength("firstName", u.FirstName, 100)
+	v.Required("lastName", u.LastName)
+	v.MaxLength("lastName", u.LastName, 100)
+	v.Check(ValidRole(u.Role), "role", "must be admin, lab_manager or operative")
+}
+
+func (s *Service) checkUsername(u *User) error {
+	taken, err := s.repo.UsernameTaken(u.Username, u.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return fmt.Errorf("username %q is taken: %w", u.Username, domain.ErrConflict)
+	}
+	return nil
+}
+
+// hashPassword replaces the plain password of u with its bcrypt hash.
+func (s *Service) hashPassword(u *User) error {
+	if u.Password == "" {
+		return nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.Password = string(hash)
+	return nil
+}
@@ -0,0 +1,44 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// SessionDuration is how long a token issued at login stays valid.
+const SessionDuration = 12 * time.Hour
+
+var ErrInvalidSession = errors.New("invalid or expired session")
+
+// StartSession issues an opaque bearer token for u. Only its hash is stored,
+// so a leaked database does not leak usable tokens.
+func (s *Service) StartSession(u User) (string, time.Time, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(raw)
+	expiresAt := time.Now().Add(SessionDuration)
+
+	if err := s.repo.CreateSession(u.ID, hashToken(token), expiresAt); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// Authenticate resolves the user owning a token issued by StartSession.
+func (s *Service) Authenticate(token string) (*User, error) {
+	u, err := s.repo.GetBySessionToken(hashToken(token), time.Now())
+	if err != nil {
+		return nil, ErrInvalidSession
+	}
+	return u, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
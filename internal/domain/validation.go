@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// FieldError describes why a single field of a payload was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError groups every field error found while validating a payload,
+// so clients get all problems at once instead of one per request.
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.Field+": "+fe.Message)
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Validator accumulates field errors. The zero value is ready to use.
+type Validator struct {
+	errs []FieldError
+}
+
+func (v *Validator) Check(ok bool, field, message string) {
+	if !ok {
+		v.errs = append(v.errs, FieldError{Field: field, Message: message})
+	}
+}
+
+func (v *Validator) Required(field, value string) {
+	v.Check(strings.TrimSpace(value) != "", field, "is required")
+}
+
+func (v *Validator) RequiredID(field string, value int) {
+	v.Check(value > 0, field, "is required")
+}
+
+func (v *Validator) RequiredDate(field string, value time.Time) {
+	v.Check(!value.IsZero(), field, "is required")
+}
+
+func (v *Validator) Positive(field string, value float64) {
+	v.Check(value > 0, field, "must be greater than zero")
+}
+
+func (v *Validator) NonNegative(field string, value float64) {
+	v.Check(value >= 0, field, "must not be negative")
+}
+
+func (v *Validator) MaxLength(field, value string, max int) {
+	v.Check(len(value) <= max, field, "is too long")
+}
+
+// HasErrors reports whether a field already failed, which lets callers skip
+// referential checks that would hit the database with a bogus value.
+func (v *Validator) HasErrors(field string) bool {
+	for _, fe := range v.errs {
+		if fe.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns a *ValidationError when any check failed, nil otherwise.
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errs}
+}
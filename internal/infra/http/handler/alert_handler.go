@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/alert"
+)
+
+type AlertHandler struct {
+	service *alert.Service
+}
+
+func NewAlertHandler(service *alert.Service) *AlertHandler {
+	return &AlertHandler{service: service}
+}
+
+func (h *AlertHandler) GetRules(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	req := q.PageRequest()
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	rules, err := h.service.GetRules(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, rules)
+}
+
+func (h *AlertHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	ruleID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	rule, err := h.service.GetRule(r.Context(), ruleID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+func (h *AlertHandler) SaveRule(w http.ResponseWriter, r *http.Request) {
+	rule := &alert.Rule{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(rule); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.SaveRule(r.Context(), rule)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (h *AlertHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	ruleID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	rule := &alert.Rule{}
+	if err := json.NewDecoder(r.Body).Decode(rule); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	rule.ID = ruleID
+
+	updated, err := h.service.UpdateRule(r.Context(), rule)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *AlertHandler) GetAlerts(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	filter := alert.Filter{
+		Status:      q.String("status"),
+		ProjectID:   q.Int("project_id"),
+		RuleID:      q.Int("rule_id"),
+		MemberID:    q.Int("member_id"),
+		PageRequest: q.PageRequest(),
+	}
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	alerts, err := h.service.GetAlerts(r.Context(), filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, alerts)
+}
+
+func (h *AlertHandler) GetAlert(w http.ResponseWriter, r *http.Request) {
+	alertID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	a, err := h.service.GetAlert(r.Context(), alertID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(a)
+}
+
+func (h *AlertHandler) AcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	alertID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	a, err := h.service.AcknowledgeAlert(r.Context(), alertID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(a)
+}
+
+func (h *AlertHandler) ResolveAlert(w http.ResponseWriter, r *http.Request) {
+	alertID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Note string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	a, err := h.service.ResolveAlert(r.Context(), alertID, body.Note)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(a)
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/audit"
+)
+
+type AuditHandler struct {
+	service *audit.Service
+}
+
+func NewAuditHandler(service *audit.Service) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+func (h *AuditHandler) GetEntries(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	filter := audit.Filter{
+		Entity:   q.String("entity"),
+		EntityID: q.Int("id"),
+		UserID:   q.Int("user_id"),
+	}
+	req := q.PageRequest()
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	entries, err := h.service.GetEntries(r.Context(), filter, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, entries)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/user"
 )
@@ -31,16 +32,26 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	token, expiresAt, err := h.service.StartSession(u)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	response := struct {
-		ID        int    `json:"id"`
-		Username  string `json:"username"`
-		FirstName string `json:"firstName"`
-		LastName  string `json:"lastName"`
+		ID        int       `json:"id"`
+		Username  string    `json:"username"`
+		FirstName string    `json:"firstName"`
+		LastName  string    `json:"lastName"`
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expiresAt"`
 	}{
 		ID:        u.ID,
 		Username:  u.Username,
 		FirstName: u.FirstName,
 		LastName:  u.LastName,
+		Token:     token,
+		ExpiresAt: expiresAt,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
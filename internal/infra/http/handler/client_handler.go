@@ -3,10 +3,8 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
 
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/client"
-	"github.com/go-chi/chi/v5"
 )
 
 type ClientHandler struct {
@@ -18,15 +16,15 @@ func NewClientHandler(service *client.Service) *ClientHandler {
 }
 
 func (h *ClientHandler) GetClient(w http.ResponseWriter, r *http.Request) {
-	clientID, err := strconv.Atoi(chi.URLParam(r, "ID"))
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	clientID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
 	}
 
 	client, err := h.service.GetClient(clientID)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 
@@ -35,16 +33,21 @@ func (h *ClientHandler) GetClient(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ClientHandler) GetAllClients(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	req := q.PageRequest()
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
 
-	clients, err := h.service.GetAllClients()
+	clients, err := h.service.GetAllClients(req)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(clients)
+	writePage(w, r, clients)
 }
 
 func (h *ClientHandler) SaveClient(w http.ResponseWriter, r *http.Request) {
@@ -54,13 +57,145 @@ func (h *ClientHandler) SaveClient(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	createdClient, err := h.service.SaveClient(client)
+	createdClient, err := h.service.SaveClient(r.Context(), client)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(createdClient)
 }
+
+func (h *ClientHandler) UpdateClient(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	client := &client.Client{}
+	if err := json.NewDecoder(r.Body).Decode(client); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	client.ID = clientID
+
+	updated, err := h.service.UpdateClient(r.Context(), client)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *ClientHandler) GetContacts(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	q := newQueryParser(r.URL.Query())
+	req := q.PageRequest()
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	contacts, err := h.service.GetContacts(clientID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, contacts)
+}
+
+func (h *ClientHandler) GetContact(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	contactID, ok := pathID(w, r, "contactID")
+	if !ok {
+		return
+	}
+
+	contact, err := h.service.GetContact(clientID, contactID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(contact)
+}
+
+func (h *ClientHandler) SaveContact(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	contact := &client.Contact{}
+	if err := json.NewDecoder(r.Body).Decode(contact); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.SaveContact(r.Context(), clientID, contact)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (h *ClientHandler) UpdateContact(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	contactID, ok := pathID(w, r, "contactID")
+	if !ok {
+		return
+	}
+
+	contact := &client.Contact{}
+	if err := json.NewDecoder(r.Body).Decode(contact); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	contact.ID = contactID
+
+	updated, err := h.service.UpdateContact(r.Context(), clientID, contact)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *ClientHandler) DeleteContact(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	contactID, ok := pathID(w, r, "contactID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteContact(r.Context(), clientID, contactID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/application"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/project"
+)
+
+type ControlChartsHandler struct {
+	service *application.ControlChartsService
+}
+
+func NewControlChartsHandler(service *application.ControlChartsService) *ControlChartsHandler {
+	return &ControlChartsHandler{service: service}
+}
+
+// GetControlCharts answers with the JSON series of every group, or with
+// the image of one chart when format is png or svg.
+func (h *ControlChartsHandler) GetControlCharts(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	filter := project.ControlFilter{
+		QualityFilter: project.QualityFilter{
+			ClientID:         q.Int("client_id"),
+			ProjectID:        q.Int("project_id"),
+			DesignResistance: q.Float("design_resistance"),
+			MixDesignCode:    q.String("mix_design_code"),
+			Supplier:         q.String("supplier"),
+			From:             q.Date("from"),
+			To:               q.Date("to"),
+			AgeDays:          q.Int("age_days"),
+		},
+		GroupBy: q.String("group_by"),
+	}
+	format := q.String("format")
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if format != "" && format != "json" {
+		image, err := h.service.RenderControlChart(filter, q.String("chart"), format)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", image.ContentType)
+		w.Write(image.File)
+		return
+	}
+
+	charts, err := h.service.GetControlCharts(filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(charts)
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/equipment"
+)
+
+type EquipmentHandler struct {
+	service *equipment.Service
+}
+
+func NewEquipmentHandler(service *equipment.Service) *EquipmentHandler {
+	return &EquipmentHandler{service: service}
+}
+
+func (h *EquipmentHandler) GetEquipmentList(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	filter := equipment.ListFilter{
+		Kind:           q.String("kind"),
+		DueBefore:      q.Date("due_before"),
+		IncludeRetired: q.Bool("include_retired"),
+		PageRequest:    q.PageRequest(),
+	}
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	list, err := h.service.GetEquipmentList(filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, list)
+}
+
+func (h *EquipmentHandler) GetEquipment(w http.ResponseWriter, r *http.Request) {
+	equipmentID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	e, err := h.service.GetEquipment(equipmentID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(e)
+}
+
+func (h *EquipmentHandler) SaveEquipment(w http.ResponseWriter, r *http.Request) {
+	e := &equipment.Equipment{}
+	if err := json.NewDecoder(r.Body).Decode(e); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.SaveEquipment(r.Context(), e)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (h *EquipmentHandler) UpdateEquipment(w http.ResponseWriter, r *http.Request) {
+	equipmentID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	e := &equipment.Equipment{}
+	if err := json.NewDecoder(r.Body).Decode(e); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	e.ID = equipmentID
+
+	updated, err := h.service.UpdateEquipment(r.Context(), e)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *EquipmentHandler) GetCalibrations(w http.ResponseWriter, r *http.Request) {
+	equipmentID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	q := newQueryParser(r.URL.Query())
+	req := q.PageRequest()
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	calibrations, err := h.service.GetCalibrations(equipmentID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, calibrations)
+}
+
+func (h *EquipmentHandler) SaveCalibration(w http.ResponseWriter, r *http.Request) {
+	equipmentID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	c := &equipment.Calibration{}
+	if err := json.NewDecoder(r.Body).Decode(c); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.SaveCalibration(r.Context(), equipmentID, c)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (h *EquipmentHandler) GetTestedMembers(w http.ResponseWriter, r *http.Request) {
+	machineID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	q := newQueryParser(r.URL.Query())
+	filter := equipment.TestedFilter{
+		MachineID:   machineID,
+		From:        q.Date("from"),
+		To:          q.Date("to"),
+		PageRequest: q.PageRequest(),
+	}
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	members, err := h.service.GetTestedMembers(filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, members)
+}
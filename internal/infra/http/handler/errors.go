@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+)
+
+// writeServiceError maps errors coming from the domain services to HTTP
+// responses. Validation failures are reported field by field.
+func writeServiceError(w http.ResponseWriter, err error) {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(validationErr)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, domain.ErrUnauthorized):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case errors.Is(err, domain.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	case errors.Is(err, domain.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errors.Is(err, domain.ErrUnavailable):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
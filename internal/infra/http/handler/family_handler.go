@@ -22,13 +22,89 @@ func (h *FamilyHandler) SaveFamily(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	createdFamily, err := h.service.SaveFamily(*family)
+	createdFamily, err := h.service.SaveFamily(r.Context(), *family)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(createdFamily)
 }
+
+func (h *FamilyHandler) GetFamilies(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	projectID := q.Int("project_id")
+	req := q.PageRequest()
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	families, err := h.service.GetFamilies(projectID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, families)
+}
+
+func (h *FamilyHandler) GetFamily(w http.ResponseWriter, r *http.Request) {
+	familyID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	family, err := h.service.GetFamilyByID(familyID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(family)
+}
+
+func (h *FamilyHandler) GetProjectFamily(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	familyID, ok := pathID(w, r, "familyID")
+	if !ok {
+		return
+	}
+
+	family, err := h.service.GetProjectFamily(projectID, familyID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(family)
+}
+
+func (h *FamilyHandler) GetProjectFamilies(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	q := newQueryParser(r.URL.Query())
+	req := q.PageRequest()
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	families, err := h.service.GetProjectFamilies(projectID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, families)
+}
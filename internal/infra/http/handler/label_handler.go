@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/application"
+)
+
+type LabelsHandler struct {
+	service *application.LabelsService
+}
+
+func NewLabelsHandler(service *application.LabelsService) *LabelsHandler {
+	return &LabelsHandler{service: service}
+}
+
+func (h *LabelsHandler) GenerateFamilyLabels(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	familyID, ok := pathID(w, r, "familyID")
+	if !ok {
+		return
+	}
+	format := r.URL.Query().Get("format")
+
+	labels, err := h.service.GenerateFamilyLabels(projectID, familyID, format)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename="+labels.Filename)
+	w.Write(labels.File)
+}
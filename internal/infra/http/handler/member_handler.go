@@ -1,9 +1,16 @@
 package handler
 
 import (
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"io"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
 )
 
@@ -23,12 +30,424 @@ func (h *MemberHandler) SaveMembers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	saved, err := h.service.SaveMembers(members)
+	saved, err := h.service.SaveMembers(r.Context(), members)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(saved)
 }
+
+func (h *MemberHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	familyID := q.Int("family_id")
+	req := q.PageRequest()
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	members, err := h.service.GetMembers(familyID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, members)
+}
+
+func (h *MemberHandler) RequestCorrection(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	var correction member.Correction
+	if err := json.NewDecoder(r.Body).Decode(&correction); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	saved, err := h.service.RequestCorrection(r.Context(), memberID, &correction)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(saved)
+}
+
+// GetMemberCorrections lists the revision history of one member.
+func (h *MemberHandler) GetMemberCorrections(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	h.writeCorrections(w, r, memberID)
+}
+
+func (h *MemberHandler) GetCorrections(w http.ResponseWriter, r *http.Request) {
+	h.writeCorrections(w, r, 0)
+}
+
+func (h *MemberHandler) writeCorrections(w http.ResponseWriter, r *http.Request, memberID int) {
+	q := newQueryParser(r.URL.Query())
+	filter := member.CorrectionFilter{MemberID: memberID, Status: q.String("status")}
+	req := q.PageRequest()
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	corrections, err := h.service.GetCorrections(filter, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, corrections)
+}
+
+type correctionReview struct {
+	Note *string `json:"note"`
+}
+
+func (h *MemberHandler) ApproveCorrection(w http.ResponseWriter, r *http.Request) {
+	h.reviewCorrection(w, r, h.service.ApproveCorrection)
+}
+
+func (h *MemberHandler) RejectCorrection(w http.ResponseWriter, r *http.Request) {
+	h.reviewCorrection(w, r, h.service.RejectCorrection)
+}
+
+func (h *MemberHandler) reviewCorrection(w http.ResponseWriter, r *http.Request, review func(context.Context, int, *string) (*member.Correction, error)) {
+	correctionID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	// El cuerpo es opcional: solo trae la nota de revisión.
+	var body correctionReview
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	correction, err := review(r.Context(), correctionID, body.Note)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(correction)
+}
+
+func (h *MemberHandler) GetFractureTypes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(member.FractureTypes)
+}
+
+func (h *MemberHandler) RecordCustodyEvent(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	event := &member.CustodyEvent{}
+	if err := json.NewDecoder(r.Body).Decode(event); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.RecordCustodyEvent(r.Context(), memberID, event)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (h *MemberHandler) GetCustodyEvents(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	q := newQueryParser(r.URL.Query())
+	req := q.PageRequest()
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	events, err := h.service.GetCustodyEvents(memberID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, events)
+}
+
+func (h *MemberHandler) GetCurrentCustody(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	event, err := h.service.GetCurrentCustody(memberID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(event)
+}
+
+func (h *MemberHandler) GetSpecimensAt(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	location := q.String("location")
+	req := q.PageRequest()
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	events, err := h.service.GetSpecimensAt(location, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, events)
+}
+
+func (h *MemberHandler) LookupLabel(w http.ResponseWriter, r *http.Request) {
+	match, err := h.service.LookupLabel(r.URL.Query().Get("code"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(match)
+}
+
+// maxExportSize bounds the machine exports accepted by IngestMachineExport.
+const maxExportSize = 5 << 20
+
+// IngestMachineExport accepts the export either as the "file" field of a
+// multipart form or as the raw request body.
+func (h *MemberHandler) IngestMachineExport(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	opts := member.IngestOptions{DryRun: q.Bool("dry_run")}
+	if machineID := q.Int("machine_id"); machineID > 0 {
+		opts.MachineID = &machineID
+	}
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxExportSize)
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "missing file: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	result, err := h.service.IngestMachineExport(r.Context(), body, opts)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *MemberHandler) GetCurve(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	curve, err := h.service.GetCurve(memberID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(curve)
+}
+
+// SaveCurve accepts the curve as JSON, or as the CSV/TXT exported by the
+// press, either raw with a text content type or as the "file" field of a
+// multipart form.
+func (h *MemberHandler) SaveCurve(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxExportSize)
+	contentType := r.Header.Get("Content-Type")
+	curve := &member.Curve{}
+	switch {
+	case strings.HasPrefix(contentType, "multipart/form-data"):
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "missing file: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		if curve, err = member.ParseCurveExport(file); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+	case strings.HasPrefix(contentType, "text/"):
+		var err error
+		if curve, err = member.ParseCurveExport(r.Body); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+	default:
+		if err := json.NewDecoder(r.Body).Decode(curve); err != nil {
+			http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	saved, err := h.service.SaveCurve(r.Context(), memberID, curve)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// GetWorkload answers JSON, or the rows as CSV with format=csv.
+func (h *MemberHandler) GetWorkload(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	filter := member.WorkloadFilter{
+		From:         q.Date("from"),
+		To:           q.Date("to"),
+		Period:       q.String("period"),
+		OperativeID:  q.Int("operative_id"),
+		ProjectID:    q.Int("project_id"),
+		SpecimenType: q.String("specimen_type"),
+	}
+	format := q.String("format")
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if err := checkExportFormat(format); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	workload, err := h.service.GetWorkload(r.Context(), filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if format != "csv" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(workload)
+		return
+	}
+
+	records := [][]string{{"period", "operative_id", "operative", "specimen_type", "project_id", "project_name", "count"}}
+	for _, row := range workload.Rows {
+		operativeID := ""
+		if row.OperativeID != nil {
+			operativeID = strconv.Itoa(*row.OperativeID)
+		}
+		records = append(records, []string{
+			row.Period,
+			operativeID,
+			row.Operative,
+			row.SpecimenType,
+			strconv.Itoa(row.ProjectID),
+			row.ProjectName,
+			strconv.Itoa(row.Count),
+		})
+	}
+	writeCSV(w, "carga_laboratorio_"+workload.From.Format("20060102")+"_"+workload.To.Format("20060102")+".csv", records)
+}
+
+// GetBacklog answers JSON, or a row per day and specimen type as CSV with
+// format=csv.
+func (h *MemberHandler) GetBacklog(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	filter := member.BacklogFilter{
+		Days:      q.Int("days"),
+		ProjectID: q.Int("project_id"),
+	}
+	format := q.String("format")
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if err := checkExportFormat(format); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	backlog, err := h.service.GetBacklog(filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if format != "csv" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(backlog)
+		return
+	}
+
+	records := [][]string{{"date", "specimen_type", "count"}}
+	for _, day := range backlog.Days {
+		types := make([]string, 0, len(day.BySpecimenType))
+		for t := range day.BySpecimenType {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		for _, t := range types {
+			records = append(records, []string{day.Date.Format("2006-01-02"), t, strconv.Itoa(day.BySpecimenType[t])})
+		}
+	}
+	writeCSV(w, "pendientes_"+backlog.Today.Format("20060102")+".csv", records)
+}
+
+func checkExportFormat(format string) error {
+	v := &domain.Validator{}
+	v.Check(format == "" || format == "json" || format == "csv", "format", "must be json or csv")
+	return v.Err()
+}
+
+func writeCSV(w http.ResponseWriter, filename string, records [][]string) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	csv.NewWriter(w).WriteAll(records)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+)
+
+func (q *queryParser) PageRequest() domain.PageRequest {
+	return domain.PageRequest{
+		Page:     q.Int("page"),
+		PageSize: q.Int("page_size"),
+		Cursor:   q.String("cursor"),
+	}
+}
+
+// writePage encodes a list envelope and advertises the neighbouring pages
+// through a Link header (RFC 8288) so clients don't need to build URLs.
+func writePage[T any](w http.ResponseWriter, r *http.Request, page *domain.Page[T]) {
+	var links []string
+	link := func(rel string, set map[string]string) {
+		query := r.URL.Query()
+		query.Del("cursor")
+		query.Del("page")
+		for k, v := range set {
+			query.Set(k, v)
+		}
+		query.Set("page_size", strconv.Itoa(page.PageSize))
+		links = append(links, fmt.Sprintf(`<%s?%s>; rel="%s"`, r.URL.Path, query.Encode(), rel))
+	}
+
+	if r.URL.Query().Get("cursor") != "" {
+		if page.HasMore {
+			link("next", map[string]string{"cursor": page.NextCursor})
+		}
+	} else {
+		lastPage := (page.Total + page.PageSize - 1) / page.PageSize
+		if lastPage < 1 {
+			lastPage = 1
+		}
+		link("first", map[string]string{"page": "1"})
+		if page.Page > 1 {
+			link("prev", map[string]string{"page": strconv.Itoa(page.Page - 1)})
+		}
+		if page.HasMore {
+			link("next", map[string]string{"page": strconv.Itoa(page.Page + 1)})
+		}
+		link("last", map[string]string{"page": strconv.Itoa(lastPage)})
+	}
+
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(page.Total))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
@@ -20,7 +20,7 @@ func NewProjectHandler(service *project.Service) *ProjectHandler {
 func (h *ProjectHandler) GetProjectByID(w http.ResponseWriter, r *http.Request, ID int) {
     project, err := h.service.GetProjectByID(ID)
     if err != nil {
-        http.Error(w, err.Error(), http.StatusInternalServerError)
+        writeServiceError(w, err)
         return
     }
 
@@ -28,14 +28,47 @@ func (h *ProjectHandler) GetProjectByID(w http.ResponseWriter, r *http.Request,
     json.NewEncoder(w).Encode(project)
 }
 
-func (h *ProjectHandler) GetProjects(w http.ResponseWriter, r *http.Request, page int) {
-	projects, err := h.service.GetProjects(page)
+func (h *ProjectHandler) GetProjects(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	filter := project.ListFilter{
+		Search:    q.String("q"),
+		ClientID:  q.Int("client_id"),
+		EntryFrom: q.Date("entry_from"),
+		EntryTo:   q.Date("entry_to"),
+		Status:    q.String("status"),
+		Sort:      q.String("sort"),
+
+		ProjectStatus: q.String("project_status"),
+		Contractor:    q.String("contractor"),
+	}
+	filter.PageRequest = q.PageRequest()
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	h.writeProjectList(w, r, filter)
+}
+
+// writeProjectList answers with project summaries unless the caller asked
+// for the full family/member tree with expand=families.
+func (h *ProjectHandler) writeProjectList(w http.ResponseWriter, r *http.Request, filter project.ListFilter) {
+	if r.URL.Query().Get("expand") == "families" {
+		projects, err := h.service.GetProjects(filter)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writePage(w, r, projects)
+		return
+	}
+
+	summaries, err := h.service.GetProjectSummaries(filter)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(projects)
+	writePage(w, r, summaries)
 }
 
 func (h *ProjectHandler) SaveProject(w http.ResponseWriter, r *http.Request) {
@@ -45,31 +78,96 @@ func (h *ProjectHandler) SaveProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	createdProject, err := h.service.SaveProject(project)
+	createdProject, err := h.service.SaveProject(r.Context(), project)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(createdProject)
 }
 
+func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	project := &project.Project{}
+	if err := json.NewDecoder(r.Body).Decode(project); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	project.ID = projectID
+
+	updated, err := h.service.UpdateProject(r.Context(), project)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
 func(h *ProjectHandler) GetProjectsByClientID(w http.ResponseWriter, r *http.Request) {
 	clientID, err := strconv.Atoi(chi.URLParam(r, "clientID"))
 
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err != nil || clientID < 1 {
+		http.Error(w, "client id must be a number greater than zero", http.StatusBadRequest)
 		return
 	}	
 
-	projects, err := h.service.GetProjectsByClientID(clientID)
+	q := newQueryParser(r.URL.Query())
+	filter := project.ListFilter{ClientID: clientID, ProjectStatus: q.String("project_status"), PageRequest: q.PageRequest()}
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	h.writeProjectList(w, r, filter)
+}
+
+func (h *ProjectHandler) GetFractureTypeStats(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
 
+	stats, err := h.service.GetFractureTypeStats(projectID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(projects)
+	json.NewEncoder(w).Encode(stats)
+}
+
+func (h *ProjectHandler) GetQualityStats(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	filter := project.QualityFilter{
+		ClientID:         q.Int("client_id"),
+		ProjectID:        q.Int("project_id"),
+		DesignResistance: q.Float("design_resistance"),
+		MixDesignCode:    q.String("mix_design_code"),
+		Supplier:         q.String("supplier"),
+		From:             q.Date("from"),
+		To:               q.Date("to"),
+		AgeDays:          q.Int("age_days"),
+	}
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	stats, err := h.service.GetQualityStats(filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
 
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
 }
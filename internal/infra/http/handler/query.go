@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// pathID reads a numeric id from the route. When it is missing or not a
+// positive number it answers 400 and returns false.
+func pathID(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, name))
+	if err != nil || id < 1 {
+		http.Error(w, name+" should be a number greater than zero", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// queryParser reads typed values from a query string, collecting a field
+// error for every value that cannot be parsed.
+type queryParser struct {
+	values url.Values
+	v      domain.Validator
+}
+
+func newQueryParser(values url.Values) *queryParser {
+	return &queryParser{values: values}
+}
+
+func (q *queryParser) String(name string) string {
+	return q.values.Get(name)
+}
+
+func (q *queryParser) Int(name string) int {
+	raw := q.values.Get(name)
+	if raw == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(raw)
+	q.v.Check(err == nil, name, "must be an integer")
+	return n
+}
+
+func (q *queryParser) Float(name string) float64 {
+	raw := q.values.Get(name)
+	if raw == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	q.v.Check(err == nil, name, "must be a number")
+	return f
+}
+
+// IntList reads a comma separated list of ids, e.g. recipients=3,7.
+func (q *queryParser) IntList(name string) []int {
+	raw := q.values.Get(name)
+	if raw == "" {
+		return nil
+	}
+	var ids []int
+	for _, part := range strings.Split(raw, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			q.v.Check(false, name, "must be a comma separated list of integers")
+			return nil
+		}
+		ids = append(ids, n)
+	}
+	return ids
+}
+
+func (q *queryParser) Bool(name string) bool {
+	raw := q.values.Get(name)
+	if raw == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(raw)
+	q.v.Check(err == nil, name, "must be true or false")
+	return b
+}
+
+func (q *queryParser) Date(name string) *time.Time {
+	raw := q.values.Get(name)
+	if raw == "" {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		q.v.Check(false, name, "must be a date formatted as YYYY-MM-DD")
+		return nil
+	}
+	return &t
+}
+
+func (q *queryParser) Err() error {
+	return q.v.Err()
+}
@@ -1,11 +1,12 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
-	"strconv"
 
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/application"
-	"github.com/go-chi/chi/v5"
 )
 
 type ReportsHandler struct {
@@ -17,22 +18,143 @@ func NewReportsHandler(service application.ReportsService) *ReportsHandler {
 }
 
 func (h *ReportsHandler)GenerateReportForOneFamily(w http.ResponseWriter, r *http.Request) {
-	projectID := chi.URLParam(r, "ID")
-	numericProjectID, err := strconv.Atoi(projectID)
-	if err != nil || numericProjectID < 1 {
-		http.Error(w, "project ID should be a number greater than zero", http.StatusBadRequest)
+	numericProjectID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
 	}
-	familyID := chi.URLParam(r, "familyID")
-	numericFamilyID, err := strconv.Atoi(familyID)
-	if err != nil || numericFamilyID < 1 {
-		http.Error(w, "family ID should be a number greater than zero", http.StatusBadRequest)
+	numericFamilyID, ok := pathID(w, r, "familyID")
+	if !ok {
+		return
 	}
-	report, err := h.ReportsService.GenerateReportForOneFamily(numericProjectID, numericFamilyID)
+	q := newQueryParser(r.URL.Query())
+	recipients := q.IntList("recipients")
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	report, err := h.ReportsService.GenerateReportForOneFamily(numericProjectID, numericFamilyID, recipients)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/pdf")
     w.Header().Set("Content-Disposition", "attachment; filename="+report.Filename)
     w.Write(report.File)
-}
\ No newline at end of file
+}
+
+// IssueReport records the report of a family as issued. The optional JSON
+// body {"recipients": [...]} picks the client contacts it is addressed to.
+func (h *ReportsHandler) IssueReport(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	familyID, ok := pathID(w, r, "familyID")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Recipients []int `json:"recipients"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	issued, err := h.ReportsService.IssueReport(r.Context(), projectID, familyID, body.Recipients)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(issued)
+}
+
+// DownloadIssuedReport returns the PDF of an issued report.
+func (h *ReportsHandler) DownloadIssuedReport(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	familyID, ok := pathID(w, r, "familyID")
+	if !ok {
+		return
+	}
+	reportID, ok := pathID(w, r, "reportID")
+	if !ok {
+		return
+	}
+
+	issued, err := h.ReportsService.GetIssuedReport(projectID, familyID, reportID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename="+issued.Filename)
+	w.Write(issued.File)
+}
+
+func (h *ReportsHandler) GetIssuedReports(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	familyID, ok := pathID(w, r, "familyID")
+	if !ok {
+		return
+	}
+	q := newQueryParser(r.URL.Query())
+	req := q.PageRequest()
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	issued, err := h.ReportsService.GetIssuedReports(projectID, familyID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, issued)
+}
+
+// SendIssuedReport emails an issued report. The optional JSON body
+// {"emails": [...]} picks the recipients to send it to again.
+func (h *ReportsHandler) SendIssuedReport(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	familyID, ok := pathID(w, r, "familyID")
+	if !ok {
+		return
+	}
+	reportID, ok := pathID(w, r, "reportID")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Emails []string `json:"emails"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	issued, err := h.ReportsService.SendIssuedReport(r.Context(), projectID, familyID, reportID, body.Emails)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(issued)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/search"
+)
+
+type SearchHandler struct {
+	service *search.Service
+}
+
+func NewSearchHandler(service *search.Service) *SearchHandler {
+	return &SearchHandler{service: service}
+}
+
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	limit := q.Int("limit")
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	hits, err := h.service.Search(q.String("q"), limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hits)
+}
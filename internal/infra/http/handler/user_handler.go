@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/user"
+)
+
+type UserHandler struct {
+	service *user.Service
+}
+
+func NewUserHandler(service *user.Service) *UserHandler {
+	return &UserHandler{service: service}
+}
+
+func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	q := newQueryParser(r.URL.Query())
+	req := q.PageRequest()
+	if err := q.Err(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	users, err := h.service.GetUsers(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writePage(w, r, users)
+}
+
+// userBody is a user as sent by the client, with its plain password.
+type userBody struct {
+	user.User
+	Password string `json:"password"`
+}
+
+func decodeUser(w http.ResponseWriter, r *http.Request) (*user.User, bool) {
+	body := userBody{User: user.User{IsActive: true}}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	u := body.User
+	u.Password = body.Password
+	return &u, true
+}
+
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	u, ok := decodeUser(w, r)
+	if !ok {
+		return
+	}
+
+	created, err := h.service.CreateUser(r.Context(), u)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateUser keeps the current password unless the body has a new one.
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(w, r, "ID")
+	if !ok {
+		return
+	}
+	u, ok := decodeUser(w, r)
+	if !ok {
+		return
+	}
+	u.ID = userID
+
+	updated, err := h.service.UpdateUser(r.Context(), u)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
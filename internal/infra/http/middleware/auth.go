@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/user"
+)
+
+// Authenticate resolves the bearer token issued at login into the user and
+// stores it in the request context. Requests without a token go through
+// anonymously; a token that is invalid or expired is rejected.
+func Authenticate(service *user.Service) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok {
+				http.Error(w, "authorization header must be a Bearer token", http.StatusUnauthorized)
+				return
+			}
+
+			u, err := service.Authenticate(strings.TrimSpace(token))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(user.WithUser(r.Context(), u)))
+		})
+	}
+}
+
+// RequireAuth rejects requests that change data, anything but GET, HEAD
+// and OPTIONS, unless Authenticate resolved a user for them, so every
+// change can be attributed in the audit trail.
+func RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			if _, ok := user.FromContext(r.Context()); !ok {
+				http.Error(w, domain.ErrUnauthorized.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
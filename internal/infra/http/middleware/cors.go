@@ -12,7 +12,7 @@ func CORS(next http.Handler) http.Handler {
 		w.Header().Set("Access-Control-Allow-Origin", origin)
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		w.Header().Set("Access-Control-Expose-Headers", "Content-Disposition")
+		w.Header().Set("Access-Control-Expose-Headers", "Content-Disposition, Link, X-Total-Count")
 
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 
@@ -0,0 +1,220 @@
+// Package mail delivers email over SMTP.
+//
+// To check messages locally without a real server, run MailHog
+// (docker run -p 1025:1025 -p 8025:8025 mailhog/mailhog), set SMTP_HOST=localhost
+// and SMTP_PORT=1025 in .env, leave SMTP_USERNAME empty so no AUTH is sent,
+// and run "go run ./cmd/mailcheck -to someone@example.com". The message, with
+// its quoted-printable body and wrapped base64 attachment, shows up at
+// http://localhost:8025.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/report"
+)
+
+// dialTimeout bounds connecting to the SMTP server when the context has no
+// deadline of its own.
+const dialTimeout = 30 * time.Second
+
+// Config is the SMTP server and the sender address.
+type Config struct {
+	Host string
+	Port int
+	// Username and Password are optional; without them no AUTH is sent,
+	// which is what local fake servers such as MailHog expect.
+	Username string
+	Password string
+	From     string
+	FromName string
+}
+
+// ConfigFromEnv reads the SMTP_* and MAIL_FROM* variables. It returns false
+// when SMTP_HOST is not set, meaning mail is disabled.
+func ConfigFromEnv() (Config, bool) {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return Config{}, false
+	}
+	port, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
+	return Config{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("MAIL_FROM"),
+		FromName: os.Getenv("MAIL_FROM_NAME"),
+	}, true
+}
+
+// SMTPSender sends each message in its own SMTP session, upgrading it with
+// STARTTLS whenever the server offers it.
+type SMTPSender struct {
+	config Config
+}
+
+func NewSMTPSender(config Config) *SMTPSender {
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	return &SMTPSender{config: config}
+}
+
+func (s *SMTPSender) Send(ctx context.Context, m *report.Message) error {
+	msg, err := s.buildMessage(m, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, dialTimeout)
+		defer cancel()
+	}
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+			return err
+		}
+	}
+	if s.config.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.config.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(m.To); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// buildMessage renders m as a multipart/mixed MIME message: the body as
+// quoted-printable text followed by the base64 attachments.
+func (s *SMTPSender) buildMessage(m *report.Message, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+
+	from := mail.Address{Name: s.config.FromName, Address: s.config.From}
+	to := mail.Address{Name: m.ToName, Address: m.To}
+	header := []struct{ key, value string }{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", m.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", messageID(s.config.From)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `multipart/mixed; boundary="` + body.Boundary() + `"`},
+	}
+	var head bytes.Buffer
+	for _, h := range header {
+		fmt.Fprintf(&head, "%s: %s\r\n", h.key, h.value)
+	}
+	head.WriteString("\r\n")
+
+	part, err := body.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(m.Body)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, a := range m.Attachments {
+		part, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(a.ContentType, map[string]string{"name": a.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64Lines(part, a.Content); err != nil {
+			return nil, err
+		}
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+
+	return append(head.Bytes(), buf.Bytes()...), nil
+}
+
+// writeBase64Lines writes content in base64, wrapped at 76 characters as
+// RFC 2045 requires.
+func writeBase64Lines(w io.Writer, content []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 0 {
+		n := min(76, len(encoded))
+		if _, err := w.Write([]byte(encoded[:n] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(addr.Address, "@"); at >= 0 {
+			domain = addr.Address[at+1:]
+		}
+	}
+	b := make([]byte, 12)
+	rand.Read(b)
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(b), domain)
+}
@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/alert"
+	"github.com/jmoiron/sqlx"
+)
+
+const ruleColumns = `id, name, threshold_percent, project_id, specimen_type, age_days, emails,
+        notify_client, webhook_url, active, created_by, created_at`
+
+const alertColumns = `a.id, a.rule_id, COALESCE(ar.name, '') AS rule_name, a.member_id, a.family_id,
+        COALESCE(f.sample_place, '') AS sample_place, COALESCE(a.project_id, 0) AS project_id,
+        COALESCE(p.name, '') AS project_name, COALESCE(p.client_id, 0) AS client_id,
+        a.result, a.strength_psi, a.design_resistance, a.percent, a.age_days, a.status, a.created_at,
+        a.acknowledged_by, a.acknowledged_at, a.resolved_by, a.resolved_at, a.resolution_note`
+
+const alertJoins = `
+        FROM alerts a
+        LEFT JOIN alert_rules ar ON ar.id = a.rule_id
+        LEFT JOIN families f ON f.id = a.family_id
+        LEFT JOIN projects p ON p.id = a.project_id`
+
+// ruleRow stores the emails of a rule as a comma separated list.
+type ruleRow struct {
+	alert.Rule
+	Emails string `db:"emails"`
+}
+
+func (row *ruleRow) rule() *alert.Rule {
+	rule := row.Rule
+	rule.Emails = []string{}
+	if row.Emails != "" {
+		rule.Emails = strings.Split(row.Emails, ",")
+	}
+	return &rule
+}
+
+type alertRepository struct {
+	db *sqlx.DB
+}
+
+func NewAlertRepository(db *sqlx.DB) alert.Repository {
+	return &alertRepository{db: db}
+}
+
+func (r *alertRepository) SaveRule(rule *alert.Rule) error {
+	result, err := r.db.Exec(`
+		INSERT INTO alert_rules (name, threshold_percent, project_id, specimen_type, age_days, emails,
+		                         notify_client, webhook_url, active, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rule.Name, rule.ThresholdPercent, rule.ProjectID, rule.SpecimenType, rule.AgeDays,
+		strings.Join(rule.Emails, ","), rule.NotifyClient, rule.WebhookURL, rule.Active,
+		rule.CreatedBy, rule.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	rule.ID = int(id)
+	return nil
+}
+
+func (r *alertRepository) UpdateRule(rule *alert.Rule) error {
+	_, err := r.db.Exec(`
+		UPDATE alert_rules
+		SET name = ?, threshold_percent = ?, project_id = ?, specimen_type = ?, age_days = ?, emails = ?,
+		    notify_client = ?, webhook_url = ?, active = ?
+		WHERE id = ?`,
+		rule.Name, rule.ThresholdPercent, rule.ProjectID, rule.SpecimenType, rule.AgeDays,
+		strings.Join(rule.Emails, ","), rule.NotifyClient, rule.WebhookURL, rule.Active, rule.ID)
+	return err
+}
+
+func (r *alertRepository) GetRule(ID int) (*alert.Rule, error) {
+	row := &ruleRow{}
+	err := r.db.Get(row, `SELECT `+ruleColumns+` FROM alert_rules WHERE id = ?`, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("alert rule %d: %w", ID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.rule(), nil
+}
+
+func (r *alertRepository) GetRules(req domain.PageRequest) ([]*alert.Rule, int, error) {
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM alert_rules`); err != nil {
+		return nil, 0, err
+	}
+
+	var rows []ruleRow
+	err := r.db.Select(&rows, `
+		SELECT `+ruleColumns+`
+		FROM alert_rules
+		WHERE id > ?
+		ORDER BY id
+		LIMIT ? OFFSET ?`, req.AfterID(), req.Limit(), req.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+	rules := make([]*alert.Rule, len(rows))
+	for i := range rows {
+		rules[i] = rows[i].rule()
+	}
+	return rules, total, nil
+}
+
+func (r *alertRepository) GetActiveRules() ([]*alert.Rule, error) {
+	var rows []ruleRow
+	if err := r.db.Select(&rows, `SELECT `+ruleColumns+` FROM alert_rules WHERE active = 1 ORDER BY id`); err != nil {
+		return nil, err
+	}
+	rules := make([]*alert.Rule, len(rows))
+	for i := range rows {
+		rules[i] = rows[i].rule()
+	}
+	return rules, nil
+}
+
+func (r *alertRepository) SaveAlert(a *alert.Alert) error {
+	result, err := r.db.Exec(`
+		INSERT INTO alerts (rule_id, member_id, family_id, project_id, result, strength_psi,
+		                    design_resistance, percent, age_days, status, created_at)
+		VALUES (?, ?, ?, NULLIF(?, 0), ?, ?, ?, ?, ?, ?, ?)`,
+		a.RuleID, a.MemberID, a.FamilyID, a.ProjectID, a.Result, a.StrengthPSI,
+		a.DesignResistance, a.Percent, a.AgeDays, a.Status, a.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	a.ID = int(id)
+	return nil
+}
+
+func (r *alertRepository) GetAlert(ID int) (*alert.Alert, error) {
+	a := &alert.Alert{}
+	err := r.db.Get(a, `SELECT `+alertColumns+alertJoins+` WHERE a.id = ?`, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("alert %d: %w", ID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.Notifications = []alert.Notification{}
+	if err := r.db.Select(&a.Notifications, `
+		SELECT id, alert_id, channel, target, status, error, sent_at
+		FROM alert_notifications
+		WHERE alert_id = ?
+		ORDER BY id`, ID); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *alertRepository) GetAlerts(filter alert.Filter) ([]*alert.Alert, int, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Status != "" {
+		conditions = append(conditions, "a.status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.ProjectID > 0 {
+		conditions = append(conditions, "a.project_id = ?")
+		args = append(args, filter.ProjectID)
+	}
+	if filter.RuleID > 0 {
+		conditions = append(conditions, "a.rule_id = ?")
+		args = append(args, filter.RuleID)
+	}
+	if filter.MemberID > 0 {
+		conditions = append(conditions, "a.member_id = ?")
+		args = append(args, filter.MemberID)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.Get(&total, "SELECT COUNT(*) FROM alerts a"+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	// Newest first, so the keyset walks ids downwards.
+	if afterID := filter.AfterID(); afterID > 0 {
+		conditions = append(conditions, "a.id < ?")
+		args = append(args, afterID)
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit(), filter.Offset())
+
+	alerts := []*alert.Alert{}
+	err := r.db.Select(&alerts, "SELECT "+alertColumns+alertJoins+where+
+		" ORDER BY a.id DESC LIMIT ? OFFSET ?", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return alerts, total, nil
+}
+
+func (r *alertRepository) HasUnresolvedAlert(ruleID, memberID int) (bool, error) {
+	var unresolved bool
+	err := r.db.Get(&unresolved, `
+		SELECT EXISTS (
+			SELECT 1 FROM alerts WHERE rule_id = ? AND member_id = ? AND status <> ?
+		)`, ruleID, memberID, alert.StatusResolved)
+	return unresolved, err
+}
+
+func (r *alertRepository) UpdateAlertStatus(a *alert.Alert, fromStatus string) error {
+	res, err := r.db.Exec(`
+		UPDATE alerts
+		SET status = ?, acknowledged_by = ?, acknowledged_at = ?, resolved_by = ?, resolved_at = ?,
+		    resolution_note = ?
+		WHERE id = ? AND status = ?`,
+		a.Status, a.AcknowledgedBy, a.AcknowledgedAt, a.ResolvedBy, a.ResolvedAt, a.ResolutionNote,
+		a.ID, fromStatus)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("alert %d is no longer %s: %w", a.ID, fromStatus, domain.ErrConflict)
+	}
+	return nil
+}
+
+func (r *alertRepository) SaveNotification(n *alert.Notification) error {
+	result, err := r.db.Exec(`
+		INSERT INTO alert_notifications (alert_id, channel, target, status, error, sent_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		n.AlertID, n.Channel, n.Target, n.Status, n.Error, n.SentAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	n.ID = int(id)
+	return nil
+}
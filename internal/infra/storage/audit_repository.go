@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/audit"
+	"github.com/jmoiron/sqlx"
+)
+
+type auditRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepository(db *sqlx.DB) audit.Repository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) SaveEntry(e *audit.Entry) error {
+	res, err := r.db.Exec(`
+		INSERT INTO audit_log (entity, entity_id, action, user_id, username, created_at, before_json, after_json, diff_json)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.Entity, e.EntityID, e.Action, e.UserID, e.Username,
+		e.CreatedAt, e.Before, e.After, e.Diff)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	e.ID = int(id)
+	return nil
+}
+
+func (r *auditRepository) GetEntries(filter audit.Filter, req domain.PageRequest) ([]*audit.Entry, int, error) {
+	where := `
+		WHERE (? = '' OR entity = ?)
+		  AND (? = 0 OR entity_id = ?)
+		  AND (? = 0 OR user_id = ?)`
+	args := []interface{}{
+		filter.Entity, filter.Entity,
+		filter.EntityID, filter.EntityID,
+		filter.UserID, filter.UserID,
+	}
+
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM audit_log`+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	// Newest first, so the keyset walks ids downwards.
+	keyset := ""
+	if afterID := req.AfterID(); afterID > 0 {
+		keyset = " AND id < ?"
+		args = append(args, afterID)
+	}
+	args = append(args, req.Limit(), req.Offset())
+
+	entries := []*audit.Entry{}
+	err := r.db.Select(&entries, `
+		SELECT id, entity, entity_id, action, user_id, username, created_at, before_json, after_json, diff_json
+		FROM audit_log`+where+keyset+`
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
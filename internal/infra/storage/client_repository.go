@@ -1,10 +1,19 @@
 package storage
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/client"
 	"github.com/jmoiron/sqlx"
 )
 
+const clientColumns = "id, name, tax_id, address, city"
+
+const contactColumns = "id, client_id, name, role, email, phone, receives_reports"
+
 type clientRepository struct {
 	db *sqlx.DB
 }
@@ -14,7 +23,8 @@ func NewClientRepository(db *sqlx.DB) *clientRepository {
 }
 
 func (r *clientRepository) SaveClient(c *client.Client) (*client.Client, error) {
-	result, err := r.db.Exec("INSERT INTO clients (name) VALUES (?)", c.Name)
+	result, err := r.db.Exec("INSERT INTO clients (name, tax_id, address, city) VALUES (?, ?, ?, ?)",
+		c.Name, c.TaxID, c.Address, c.City)
 	if err != nil {
 		return nil, err
 	}
@@ -25,24 +35,50 @@ func (r *clientRepository) SaveClient(c *client.Client) (*client.Client, error)
 	}
 
 	return &client.Client{
-		ID:   int(id),
-		Name: c.Name,
+		ID:      int(id),
+		Name:    c.Name,
+		TaxID:   c.TaxID,
+		Address: c.Address,
+		City:    c.City,
 	}, nil
 }
 
+func (r *clientRepository) UpdateClient(c *client.Client) error {
+	_, err := r.db.Exec("UPDATE clients SET name = ?, tax_id = ?, address = ?, city = ? WHERE id = ?",
+		c.Name, c.TaxID, c.Address, c.City, c.ID)
+	return err
+}
+
 func (r *clientRepository) GetClient(ID int) (*client.Client, error) {
-	clientRow := r.db.QueryRowx("SELECT id, name FROM clients WHERE id = ?", ID)
+	clientRow := r.db.QueryRowx("SELECT "+clientColumns+" FROM clients WHERE id = ?", ID)
 	client := &client.Client{}
 	if err := clientRow.StructScan(client); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("client %d: %w", ID, domain.ErrNotFound)
+		}
 		return nil, err
 	}
+
+	contacts, err := r.GetAllContacts(ID)
+	if err != nil {
+		return nil, err
+	}
+	client.Contacts = contacts
 	return client, nil
 }
 
-func (r *clientRepository) GetAllClients() ([]*client.Client, error) {
-	rows, err := r.db.Queryx("SELECT id, name FROM clients")
+func (r *clientRepository) GetAllClients(req domain.PageRequest) ([]*client.Client, int, error) {
+	var total int
+	if err := r.db.Get(&total, "SELECT COUNT(*) FROM clients"); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Queryx(
+		"SELECT "+clientColumns+" FROM clients WHERE id > ? ORDER BY id LIMIT ? OFFSET ?",
+		req.AfterID(), req.Limit(), req.Offset(),
+	)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -51,15 +87,96 @@ func (r *clientRepository) GetAllClients() ([]*client.Client, error) {
 	for rows.Next() {
 		c := &client.Client{}
 		if err := rows.StructScan(c); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		clients = append(clients, c)
 	}
 
 	// Siempre chequear errores del iterador
 	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return clients, total, nil
+}
+
+func (r *clientRepository) SaveContact(c *client.Contact) error {
+	result, err := r.db.Exec(`
+		INSERT INTO client_contacts (client_id, name, role, email, phone, receives_reports)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		c.ClientID, c.Name, c.Role, c.Email, c.Phone, c.ReceivesReports)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	c.ID = int(id)
+	return nil
+}
+
+func (r *clientRepository) UpdateContact(c *client.Contact) error {
+	_, err := r.db.Exec(`
+		UPDATE client_contacts
+		SET name = ?, role = ?, email = ?, phone = ?, receives_reports = ?
+		WHERE id = ? AND client_id = ?`,
+		c.Name, c.Role, c.Email, c.Phone, c.ReceivesReports, c.ID, c.ClientID)
+	return err
+}
+
+func (r *clientRepository) DeleteContact(clientID, contactID int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Los reportes emitidos conservan nombre y correo del destinatario.
+	if _, err := tx.Exec(`UPDATE issued_report_recipients SET contact_id = NULL WHERE contact_id = ?`, contactID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM client_contacts WHERE id = ? AND client_id = ?`, contactID, clientID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *clientRepository) GetContact(clientID, contactID int) (*client.Contact, error) {
+	c := &client.Contact{}
+	err := r.db.Get(c, "SELECT "+contactColumns+" FROM client_contacts WHERE id = ? AND client_id = ?", contactID, clientID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("contact %d of client %d: %w", contactID, clientID, domain.ErrNotFound)
+	}
+	if err != nil {
 		return nil, err
 	}
+	return c, nil
+}
+
+func (r *clientRepository) GetContacts(clientID int, req domain.PageRequest) ([]*client.Contact, int, error) {
+	var total int
+	if err := r.db.Get(&total, "SELECT COUNT(*) FROM client_contacts WHERE client_id = ?", clientID); err != nil {
+		return nil, 0, err
+	}
+
+	contacts := []*client.Contact{}
+	err := r.db.Select(&contacts, `
+		SELECT `+contactColumns+`
+		FROM client_contacts
+		WHERE client_id = ? AND id > ?
+		ORDER BY id
+		LIMIT ? OFFSET ?`,
+		clientID, req.AfterID(), req.Limit(), req.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+	return contacts, total, nil
+}
 
-	return clients, nil
+func (r *clientRepository) GetAllContacts(clientID int) ([]client.Contact, error) {
+	var contacts []client.Contact
+	err := r.db.Select(&contacts, "SELECT "+contactColumns+" FROM client_contacts WHERE client_id = ? ORDER BY id", clientID)
+	return contacts, err
 }
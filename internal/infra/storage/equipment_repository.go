@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/equipment"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
+	"github.com/jmoiron/sqlx"
+)
+
+const equipmentColumns = `e.id, e.kind, e.name, e.manufacturer, e.model, e.serial_number, e.capacity_kn,
+        e.machine_id, e.retired,
+        (SELECT MAX(c.valid_until) FROM equipment_calibrations c WHERE c.equipment_id = e.id) AS calibrated_until`
+
+const calibrationColumns = "id, equipment_id, certificate_number, laboratory, calibrated_on, valid_until, notes"
+
+type equipmentRepository struct {
+	db *sqlx.DB
+}
+
+func NewEquipmentRepository(db *sqlx.DB) *equipmentRepository {
+	return &equipmentRepository{db: db}
+}
+
+func (r *equipmentRepository) SaveEquipment(e *equipment.Equipment) error {
+	result, err := r.db.Exec(`
+		INSERT INTO equipment (kind, name, manufacturer, model, serial_number, capacity_kn, machine_id, retired)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.Kind, e.Name, e.Manufacturer, e.Model, e.SerialNumber, e.CapacityKN, e.MachineID, e.Retired)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	e.ID = int(id)
+	return nil
+}
+
+func (r *equipmentRepository) UpdateEquipment(e *equipment.Equipment) error {
+	_, err := r.db.Exec(`
+		UPDATE equipment
+		SET kind = ?, name = ?, manufacturer = ?, model = ?, serial_number = ?, capacity_kn = ?,
+		    machine_id = ?, retired = ?
+		WHERE id = ?`,
+		e.Kind, e.Name, e.Manufacturer, e.Model, e.SerialNumber, e.CapacityKN, e.MachineID, e.Retired, e.ID)
+	return err
+}
+
+func (r *equipmentRepository) GetEquipment(ID int) (*equipment.Equipment, error) {
+	e := &equipment.Equipment{}
+	err := r.db.Get(e, "SELECT "+equipmentColumns+" FROM equipment e WHERE e.id = ?", ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("equipment %d: %w", ID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Select(&e.Calibrations, `
+		SELECT `+calibrationColumns+`
+		FROM equipment_calibrations
+		WHERE equipment_id = ?
+		ORDER BY valid_until DESC, id DESC`, ID); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *equipmentRepository) GetEquipmentList(filter equipment.ListFilter) ([]*equipment.Equipment, int, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Kind != "" {
+		conditions = append(conditions, "e.kind = ?")
+		args = append(args, filter.Kind)
+	}
+	if !filter.IncludeRetired {
+		conditions = append(conditions, "e.retired = 0")
+	}
+	if filter.DueBefore != nil {
+		conditions = append(conditions, `COALESCE((SELECT MAX(c.valid_until) FROM equipment_calibrations c
+            WHERE c.equipment_id = e.id), '') < ?`)
+		args = append(args, filter.DueBefore.Format("2006-01-02"))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.Get(&total, "SELECT COUNT(*) FROM equipment e"+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	keyset := "e.id > ?"
+	if where == "" {
+		keyset = " WHERE " + keyset
+	} else {
+		keyset = " AND " + keyset
+	}
+	args = append(args, filter.AfterID(), filter.Limit(), filter.Offset())
+
+	var list []*equipment.Equipment
+	err := r.db.Select(&list, "SELECT "+equipmentColumns+" FROM equipment e"+where+keyset+
+		" ORDER BY e.id LIMIT ? OFFSET ?", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+func (r *equipmentRepository) SaveCalibration(c *equipment.Calibration) error {
+	result, err := r.db.Exec(`
+		INSERT INTO equipment_calibrations (equipment_id, certificate_number, laboratory, calibrated_on, valid_until, notes)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		c.EquipmentID, c.CertificateNumber, c.Laboratory, c.CalibratedOn, c.ValidUntil, c.Notes)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	c.ID = int(id)
+	return nil
+}
+
+func (r *equipmentRepository) GetCalibrations(equipmentID int, req domain.PageRequest) ([]*equipment.Calibration, int, error) {
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM equipment_calibrations WHERE equipment_id = ?`, equipmentID); err != nil {
+		return nil, 0, err
+	}
+
+	// Newest first, so the keyset walks ids downwards.
+	args := []interface{}{equipmentID}
+	keyset := ""
+	if afterID := req.AfterID(); afterID > 0 {
+		keyset = " AND id < ?"
+		args = append(args, afterID)
+	}
+	args = append(args, req.Limit(), req.Offset())
+
+	var calibrations []*equipment.Calibration
+	err := r.db.Select(&calibrations, `
+		SELECT `+calibrationColumns+`
+		FROM equipment_calibrations
+		WHERE equipment_id = ?`+keyset+`
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return calibrations, total, nil
+}
+
+func (r *equipmentRepository) GetTestedMembers(filter equipment.TestedFilter) ([]*member.Member, int, error) {
+	// Los miembros antiguos solo tienen date_of_fracture.
+	where := " WHERE machine_id = ?"
+	args := []interface{}{filter.MachineID}
+	if filter.From != nil {
+		where += " AND substr(COALESCE(fractured_at, date_of_fracture), 1, 10) >= ?"
+		args = append(args, filter.From.Format("2006-01-02"))
+	}
+	if filter.To != nil {
+		where += " AND substr(COALESCE(fractured_at, date_of_fracture), 1, 10) <= ?"
+		args = append(args, filter.To.Format("2006-01-02"))
+	}
+
+	var total int
+	if err := r.db.Get(&total, "SELECT COUNT(*) FROM members"+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	// Newest first, so the keyset walks ids downwards.
+	if afterID := filter.AfterID(); afterID > 0 {
+		where += " AND id < ?"
+		args = append(args, afterID)
+	}
+	args = append(args, filter.Limit(), filter.Offset())
+
+	var members []*member.Member
+	err := r.db.Select(&members, "SELECT "+memberColumns+" FROM members"+where+
+		" ORDER BY id DESC LIMIT ? OFFSET ?", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return members, total, nil
+}
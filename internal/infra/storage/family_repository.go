@@ -1,10 +1,21 @@
 package storage
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/family"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/user"
 	"github.com/jmoiron/sqlx"
 )
 
+const familyColumns = `id, type, date_of_entry, radius, height, classification, client_id, project_id, design_resistance, sample_place,
+		slump_cm, concrete_temperature_c, ambient_temperature_c, supplier, truck_ticket, mix_design_code,
+		structural_element, sampling_technician, specimen_type, side, width, depth, span`
+
 type familyRepository struct {
 	db *sqlx.DB
 }
@@ -24,9 +35,22 @@ func (r *familyRepository) SaveFamily(family *family.Family) (*family.Family, er
 			client_id,
 			project_id,
 			sample_place,
-			design_resistance
+			design_resistance,
+			slump_cm,
+			concrete_temperature_c,
+			ambient_temperature_c,
+			supplier,
+			truck_ticket,
+			mix_design_code,
+			structural_element,
+			sampling_technician,
+			specimen_type,
+			side,
+			width,
+			depth,
+			span
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	res, err := r.db.Exec(
@@ -40,6 +64,19 @@ func (r *familyRepository) SaveFamily(family *family.Family) (*family.Family, er
 		family.ProjectID,
 		family.SamplePlace,
 		family.DesignResistance,
+		family.SlumpCM,
+		family.ConcreteTemperatureC,
+		family.AmbientTemperatureC,
+		family.Supplier,
+		family.TruckTicket,
+		family.MixDesignCode,
+		family.StructuralElement,
+		family.SamplingTechnician,
+		family.SpecimenType,
+		family.Side,
+		family.Width,
+		family.Depth,
+		family.Span,
 	)
 	if err != nil {
 		return nil, err
@@ -53,3 +90,132 @@ func (r *familyRepository) SaveFamily(family *family.Family) (*family.Family, er
 	family.ID = int(id)
 	return family, nil
 }
+
+func (r *familyRepository) GetProjectClientID(projectID int) (int, bool, error) {
+	var clientID int
+	err := r.db.Get(&clientID, `SELECT client_id FROM projects WHERE id = ?`, projectID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return clientID, true, nil
+}
+
+func (r *familyRepository) GetFamilies(projectID int, req domain.PageRequest) ([]*family.Family, int, error) {
+	var total int
+	if err := r.db.Get(&total, `
+		SELECT COUNT(*) FROM families
+		WHERE (? = 0 OR project_id = ?)`, projectID, projectID); err != nil {
+		return nil, 0, err
+	}
+
+	var families []*family.Family
+	err := r.db.Select(&families, `
+		SELECT `+familyColumns+`
+		FROM families
+		WHERE (? = 0 OR project_id = ?) AND id > ?
+		ORDER BY id
+		LIMIT ? OFFSET ?`,
+		projectID, projectID, req.AfterID(), req.Limit(), req.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return families, total, nil
+}
+
+func (r *familyRepository) GetFamilyByID(ID int) (*family.Family, error) {
+	f := &family.Family{}
+	err := r.db.Get(f, `
+		SELECT `+familyColumns+`
+		FROM families
+		WHERE id = ?`, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("family %d: %w", ID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.LoadMembers([]*family.Family{f}); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (r *familyRepository) LoadMembers(families []*family.Family) error {
+	if len(families) == 0 {
+		return nil
+	}
+
+	familyIDs := make([]int, len(families))
+	familyMap := make(map[int]*family.Family, len(families))
+	for i, f := range families {
+		familyIDs[i] = f.ID
+		familyMap[f.ID] = f
+		f.Members = []member.Member{}
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT `+memberColumns+`
+		FROM members
+		WHERE family_id IN (?)
+		ORDER BY id`, familyIDs)
+	if err != nil {
+		return err
+	}
+
+	var members []member.Member
+	if err := r.db.Select(&members, r.db.Rebind(query), args...); err != nil {
+		return err
+	}
+
+	operatives, err := loadOperatives(r.db, members)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range members {
+		if m.OperativeID != nil {
+			m.Operative = operatives[*m.OperativeID]
+		}
+		familyMap[m.FamilyID].Members = append(familyMap[m.FamilyID].Members, m)
+	}
+	return nil
+}
+
+// loadOperatives fetches, in one query, the users that fractured the members.
+func loadOperatives(db *sqlx.DB, members []member.Member) (map[int]*user.User, error) {
+	ids := make([]int, 0)
+	seen := make(map[int]bool)
+	for _, m := range members {
+		if m.OperativeID != nil && *m.OperativeID != 0 && !seen[*m.OperativeID] {
+			ids = append(ids, *m.OperativeID)
+			seen[*m.OperativeID] = true
+		}
+	}
+
+	operatives := make(map[int]*user.User, len(ids))
+	if len(ids) == 0 {
+		return operatives, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT id, first_name, last_name, is_active
+		FROM users
+		WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []user.User
+	if err := db.Select(&users, db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	for i := range users {
+		operatives[users[i].ID] = &users[i]
+	}
+	return operatives, nil
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
+	"github.com/jmoiron/sqlx"
+)
+
+const correctionColumns = `id, member_id, previous_result, proposed_result, reason, status,
+		requested_by, requested_at, reviewed_by, reviewed_at, review_note`
+
+func (r *MemberRepository) SaveCorrection(c *member.Correction) error {
+	res, err := r.db.Exec(`
+		INSERT INTO member_corrections (member_id, previous_result, proposed_result, reason, status, requested_by, requested_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		c.MemberID, c.PreviousResult, c.ProposedResult, c.Reason, c.Status, c.RequestedBy, c.RequestedAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	c.ID = int(id)
+	return nil
+}
+
+func (r *MemberRepository) GetCorrectionByID(ID int) (*member.Correction, error) {
+	c := &member.Correction{}
+	err := r.db.Get(c, `SELECT `+correctionColumns+` FROM member_corrections WHERE id = ?`, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("correction %d: %w", ID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *MemberRepository) GetCorrections(filter member.CorrectionFilter, req domain.PageRequest) ([]*member.Correction, int, error) {
+	where := `
+		WHERE (? = 0 OR member_id = ?)
+		  AND (? = '' OR status = ?)`
+	args := []interface{}{
+		filter.MemberID, filter.MemberID,
+		filter.Status, filter.Status,
+	}
+
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM member_corrections`+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	// Newest first, so the keyset walks ids downwards.
+	keyset := ""
+	if afterID := req.AfterID(); afterID > 0 {
+		keyset = " AND id < ?"
+		args = append(args, afterID)
+	}
+	args = append(args, req.Limit(), req.Offset())
+
+	corrections := []*member.Correction{}
+	err := r.db.Select(&corrections, `
+		SELECT `+correctionColumns+`
+		FROM member_corrections`+where+keyset+`
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return corrections, total, nil
+}
+
+func (r *MemberRepository) HasPendingCorrection(memberID int) (bool, error) {
+	var pending bool
+	err := r.db.Get(&pending, `
+		SELECT EXISTS (
+			SELECT 1 FROM member_corrections WHERE member_id = ? AND status = ?
+		)`, memberID, member.CorrectionPending)
+	return pending, err
+}
+
+func (r *MemberRepository) ApproveCorrection(c *member.Correction) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := reviewCorrection(tx, c); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE members SET result = ? WHERE id = ?`, c.ProposedResult, c.MemberID); err != nil {
+		return err
+	}
+
+	// Los reportes ya emitidos de la familia quedan reemplazados.
+	if _, err := tx.Exec(`
+		UPDATE issued_reports
+		SET superseded_at = ?, superseded_by_correction_id = ?
+		WHERE superseded_at IS NULL
+		  AND family_id = (SELECT family_id FROM members WHERE id = ?)`,
+		c.ReviewedAt, c.ID, c.MemberID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *MemberRepository) RejectCorrection(c *member.Correction) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := reviewCorrection(tx, c); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// reviewCorrection stores the review of c, provided nobody reviewed it in
+// the meantime.
+func reviewCorrection(tx *sqlx.Tx, c *member.Correction) error {
+	res, err := tx.Exec(`
+		UPDATE member_corrections
+		SET status = ?, reviewed_by = ?, reviewed_at = ?, review_note = ?
+		WHERE id = ? AND status = ?`,
+		c.Status, c.ReviewedBy, c.ReviewedAt, c.ReviewNote, c.ID, member.CorrectionPending)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("correction %d is no longer pending: %w", c.ID, domain.ErrConflict)
+	}
+	return nil
+}
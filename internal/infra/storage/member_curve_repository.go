@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"bytes"
+	"compress/zlib"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
+	"github.com/jmoiron/sqlx"
+)
+
+const curveColumns = "member_id, axis, point_count, points, peak_load, recorded_by, recorded_at"
+
+// curveRow is a member_curves row with its points still encoded.
+type curveRow struct {
+	member.Curve
+	PointCount int    `db:"point_count"`
+	Data       []byte `db:"points"`
+}
+
+func (row *curveRow) decode() (*member.Curve, error) {
+	points, err := decodeCurvePoints(row.Data, row.PointCount)
+	if err != nil {
+		return nil, fmt.Errorf("curve of member %d: %w", row.MemberID, err)
+	}
+	c := row.Curve
+	c.Points = points
+	return &c, nil
+}
+
+// encodeCurvePoints packs the points as little-endian float32 (x, load)
+// pairs and compresses them. float32 keeps loads to well under 0.01 kN.
+func encodeCurvePoints(points []member.CurvePoint) ([]byte, error) {
+	raw := make([]byte, 8*len(points))
+	for i, p := range points {
+		binary.LittleEndian.PutUint32(raw[8*i:], math.Float32bits(float32(p.X)))
+		binary.LittleEndian.PutUint32(raw[8*i+4:], math.Float32bits(float32(p.Load)))
+	}
+
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write(raw); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeCurvePoints(data []byte, count int) ([]member.CurvePoint, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	raw := make([]byte, 8*count)
+	if _, err := io.ReadFull(zr, raw); err != nil {
+		return nil, err
+	}
+	points := make([]member.CurvePoint, count)
+	for i := range points {
+		points[i].X = readFloat32(raw[8*i:])
+		points[i].Load = readFloat32(raw[8*i+4:])
+	}
+	return points, nil
+}
+
+// readFloat32 widens a stored float32 to the float64 with the same shortest
+// decimal form, so 39.16 reads back as 39.16 rather than 39.15999984741211.
+func readFloat32(b []byte) float64 {
+	f := math.Float32frombits(binary.LittleEndian.Uint32(b))
+	v, _ := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'g', -1, 32), 64)
+	return v
+}
+
+func (r *MemberRepository) SaveCurve(c *member.Curve) error {
+	return saveCurve(r.db, c)
+}
+
+func saveCurve(db sqlx.Execer, c *member.Curve) error {
+	data, err := encodeCurvePoints(c.Points)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`
+		INSERT INTO member_curves (member_id, axis, point_count, points, peak_load, recorded_by, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (member_id) DO UPDATE SET
+			axis = excluded.axis,
+			point_count = excluded.point_count,
+			points = excluded.points,
+			peak_load = excluded.peak_load,
+			recorded_by = excluded.recorded_by,
+			recorded_at = excluded.recorded_at`,
+		c.MemberID, c.Axis, len(c.Points), data, c.PeakLoad, c.RecordedBy, c.RecordedAt)
+	return err
+}
+
+func (r *MemberRepository) GetCurve(memberID int) (*member.Curve, error) {
+	row := &curveRow{}
+	err := r.db.Get(row, `SELECT `+curveColumns+` FROM member_curves WHERE member_id = ?`, memberID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("curve of member %d: %w", memberID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.decode()
+}
+
+func (r *MemberRepository) GetCurves(memberIDs []int) (map[int]*member.Curve, error) {
+	query, args, err := sqlx.In(`SELECT `+curveColumns+` FROM member_curves WHERE member_id IN (?)`, memberIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*curveRow
+	if err := r.db.Select(&rows, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	curves := make(map[int]*member.Curve, len(rows))
+	for _, row := range rows {
+		c, err := row.decode()
+		if err != nil {
+			return nil, err
+		}
+		curves[c.MemberID] = c
+	}
+	return curves, nil
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
+)
+
+const custodyColumns = "id, member_id, event, location, temperature_c, notes, recorded_by, occurred_at"
+
+// currentCustody keeps the latest custody event of each member. Events are
+// appended in chronological order, so the latest one has the highest id.
+const currentCustody = `id IN (SELECT MAX(id) FROM member_custody_events GROUP BY member_id)`
+
+func (r *MemberRepository) SaveCustodyEvent(e *member.CustodyEvent) error {
+	res, err := r.db.Exec(`
+		INSERT INTO member_custody_events (member_id, event, location, temperature_c, notes, recorded_by, occurred_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		e.MemberID, e.Event, e.Location, e.TemperatureC, e.Notes, e.RecordedBy, e.OccurredAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	e.ID = int(id)
+	return nil
+}
+
+func (r *MemberRepository) GetCustodyEvents(memberID int, req domain.PageRequest) ([]*member.CustodyEvent, int, error) {
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM member_custody_events WHERE member_id = ?`, memberID); err != nil {
+		return nil, 0, err
+	}
+
+	var events []*member.CustodyEvent
+	err := r.db.Select(&events, `
+		SELECT `+custodyColumns+`
+		FROM member_custody_events
+		WHERE member_id = ? AND id > ?
+		ORDER BY id
+		LIMIT ? OFFSET ?`,
+		memberID, req.AfterID(), req.Limit(), req.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+func (r *MemberRepository) GetLastCustodyEvent(memberID int) (*member.CustodyEvent, error) {
+	e := &member.CustodyEvent{}
+	err := r.db.Get(e, `
+		SELECT `+custodyColumns+`
+		FROM member_custody_events
+		WHERE member_id = ?
+		ORDER BY id DESC
+		LIMIT 1`, memberID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *MemberRepository) HasCustodyEvent(memberID int, event string) (bool, error) {
+	var exists bool
+	err := r.db.Get(&exists, `
+		SELECT EXISTS(SELECT 1 FROM member_custody_events WHERE member_id = ? AND event = ?)`,
+		memberID, event)
+	return exists, err
+}
+
+func (r *MemberRepository) GetSpecimensAt(location string, req domain.PageRequest) ([]*member.CustodyEvent, int, error) {
+	where := ` WHERE ` + currentCustody + ` AND location = ? AND event <> ?`
+	args := []interface{}{location, member.CustodyRemoved}
+
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM member_custody_events`+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, req.AfterID(), req.Limit(), req.Offset())
+	var events []*member.CustodyEvent
+	err := r.db.Select(&events, `
+		SELECT `+custodyColumns+`
+		FROM member_custody_events`+where+` AND id > ?
+		ORDER BY id
+		LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
@@ -1,10 +1,21 @@
 package storage
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
 	"github.com/jmoiron/sqlx"
 )
 
+// memberColumns are the members columns scanned into member.Member.
+const memberColumns = `id, family_id, result, date_of_fracture, fractured_at, is_reported, operative,
+		fracture_days, fracture_type, diameter_1_cm, diameter_2_cm, height_cm, mass_kg,
+		perpendicular, capping_method, planeness_ok, machine_id`
+
 type MemberRepository struct {
 	db *sqlx.DB
 }
@@ -27,9 +38,18 @@ func (r *MemberRepository) SaveMembers(members []*member.Member) ([]*member.Memb
 			fractured_at,
 			is_reported,
 			fracture_days,
-			operative
+			operative,
+			fracture_type,
+			diameter_1_cm,
+			diameter_2_cm,
+			height_cm,
+			mass_kg,
+			perpendicular,
+			capping_method,
+			planeness_ok,
+			machine_id
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	for _, m := range members {
@@ -42,6 +62,15 @@ func (r *MemberRepository) SaveMembers(members []*member.Member) ([]*member.Memb
 			m.IsReported,
 			m.FractureDays,
 			m.OperativeID,
+			m.FractureType,
+			m.Diameter1CM,
+			m.Diameter2CM,
+			m.HeightCM,
+			m.MassKG,
+			m.Perpendicular,
+			m.CappingMethod,
+			m.PlanenessOK,
+			m.MachineID,
 		)
 		if err != nil {
 			tx.Rollback()
@@ -63,3 +92,131 @@ func (r *MemberRepository) SaveMembers(members []*member.Member) ([]*member.Memb
 
 	return members, nil
 }
+
+func (r *MemberRepository) FamilyShapes(familyIDs []int) (map[int]member.FamilyShape, error) {
+	query, args, err := sqlx.In(`SELECT id, specimen_type, COALESCE(radius, 0) AS radius, COALESCE(height, 0) AS height FROM families WHERE id IN (?)`, familyIDs)
+	if err != nil {
+		return nil, err
+	}
+	query = r.db.Rebind(query)
+
+	var rows []struct {
+		ID int `db:"id"`
+		member.FamilyShape
+	}
+	if err := r.db.Select(&rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	shapes := make(map[int]member.FamilyShape, len(rows))
+	for _, row := range rows {
+		shapes[row.ID] = row.FamilyShape
+	}
+	return shapes, nil
+}
+
+func (r *MemberRepository) RecordResult(m *member.Member, curve *member.Curve) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		UPDATE members
+		SET result = ?,
+		    fractured_at = ?,
+		    operative = ?,
+		    machine_id = ?
+		WHERE id = ? AND result IS NULL`,
+		m.Result, m.FracturedAt, m.OperativeID, m.MachineID, m.ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("member %d already has a result: %w", m.ID, domain.ErrConflict)
+	}
+
+	if curve != nil {
+		if err := saveCurve(tx, curve); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *MemberRepository) FamilyProjectID(familyID int) (int, error) {
+	var projectID int
+	err := r.db.Get(&projectID, `SELECT COALESCE(project_id, 0) FROM families WHERE id = ?`, familyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("family %d: %w", familyID, domain.ErrNotFound)
+	}
+	return projectID, err
+}
+
+func (r *MemberRepository) MachineCalibration(machineID int, day time.Time) (bool, bool, error) {
+	var status struct {
+		InService  bool `db:"in_service"`
+		Calibrated bool `db:"calibrated"`
+	}
+	date := day.Format("2006-01-02")
+	err := r.db.Get(&status, `
+		SELECT
+		    EXISTS (SELECT 1 FROM equipment WHERE id = ? AND kind = 'machine' AND retired = 0) AS in_service,
+		    (EXISTS (SELECT 1 FROM equipment_calibrations
+		             WHERE equipment_id = ? AND calibrated_on <= ? AND valid_until >= ?)
+		     AND NOT EXISTS (
+		        SELECT 1 FROM equipment cell
+		        WHERE cell.machine_id = ? AND cell.retired = 0
+		          AND NOT EXISTS (SELECT 1 FROM equipment_calibrations c
+		                          WHERE c.equipment_id = cell.id AND c.calibrated_on <= ? AND c.valid_until >= ?))
+		    ) AS calibrated`,
+		machineID, machineID, date, date, machineID, date, date)
+	if err != nil {
+		return false, false, err
+	}
+	return status.InService, status.Calibrated, nil
+}
+
+func (r *MemberRepository) GetMembers(familyID int, req domain.PageRequest) ([]*member.Member, int, error) {
+	var total int
+	if err := r.db.Get(&total, `
+		SELECT COUNT(*) FROM members
+		WHERE (? = 0 OR family_id = ?)`, familyID, familyID); err != nil {
+		return nil, 0, err
+	}
+
+	var members []*member.Member
+	err := r.db.Select(&members, `
+		SELECT `+memberColumns+`
+		FROM members
+		WHERE (? = 0 OR family_id = ?) AND id > ?
+		ORDER BY id
+		LIMIT ? OFFSET ?`,
+		familyID, familyID, req.AfterID(), req.Limit(), req.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return members, total, nil
+}
+
+func (r *MemberRepository) GetMemberByID(ID int) (*member.Member, error) {
+	m := &member.Member{}
+	err := r.db.Get(m, `
+		SELECT `+memberColumns+`
+		FROM members
+		WHERE id = ?`, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("member %d: %w", ID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"strings"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
+)
+
+// fractureDay is the day a member was broken, as YYYY-MM-DD.
+const fractureDay = `substr(COALESCE(m.fractured_at, m.date_of_fracture), 1, 10)`
+
+// dueDay is the day a pending member is due for fracture: its planned
+// fracture date, or its family's date of entry plus its age.
+const dueDay = `COALESCE(substr(m.date_of_fracture, 1, 10),
+		date(substr(f.date_of_entry, 1, 10), '+' || m.fracture_days || ' days'))`
+
+// workloadPeriods maps each member.Period to the SQL naming the period a
+// fracture day falls in.
+var workloadPeriods = map[string]string{
+	member.PeriodDay:   fractureDay,
+	member.PeriodWeek:  `date(` + fractureDay + `, 'weekday 0', '-6 days')`,
+	member.PeriodMonth: `substr(` + fractureDay + `, 1, 7)`,
+}
+
+func (r *MemberRepository) GetWorkload(filter member.WorkloadFilter) ([]member.WorkloadRow, error) {
+	conditions := []string{`m.result IS NOT NULL`, fractureDay + ` >= ?`, fractureDay + ` <= ?`}
+	args := []interface{}{filter.From.Format("2006-01-02"), filter.To.Format("2006-01-02")}
+	if filter.OperativeID > 0 {
+		conditions = append(conditions, `m.operative = ?`)
+		args = append(args, filter.OperativeID)
+	}
+	if filter.ProjectID > 0 {
+		conditions = append(conditions, `f.project_id = ?`)
+		args = append(args, filter.ProjectID)
+	}
+	if filter.SpecimenType != "" {
+		conditions = append(conditions, `f.specimen_type = ?`)
+		args = append(args, filter.SpecimenType)
+	}
+
+	var rows []member.WorkloadRow
+	err := r.db.Select(&rows, `
+		SELECT `+workloadPeriods[filter.Period]+` AS period,
+		       m.operative AS operative_id,
+		       COALESCE(u.first_name || ' ' || u.last_name, '') AS operative,
+		       f.specimen_type,
+		       COALESCE(f.project_id, 0) AS project_id,
+		       COALESCE(p.name, '') AS project_name,
+		       COUNT(*) AS count
+		FROM members m
+		JOIN families f ON f.id = m.family_id
+		LEFT JOIN projects p ON p.id = f.project_id
+		LEFT JOIN users u ON u.id = m.operative
+		WHERE `+strings.Join(conditions, " AND ")+`
+		GROUP BY period, m.operative, f.specimen_type, f.project_id
+		ORDER BY period, operative, f.specimen_type, f.project_id`, args...)
+	return rows, err
+}
+
+func (r *MemberRepository) GetDueCounts(projectID int, until time.Time) ([]member.DueCount, error) {
+	where := `m.result IS NULL AND (` + dueDay + ` IS NULL OR ` + dueDay + ` <= ?)`
+	args := []interface{}{until.Format("2006-01-02")}
+	if projectID > 0 {
+		where += ` AND f.project_id = ?`
+		args = append(args, projectID)
+	}
+
+	var counts []member.DueCount
+	err := r.db.Select(&counts, `
+		SELECT `+dueDay+` AS day, f.specimen_type, COUNT(*) AS count
+		FROM members m
+		JOIN families f ON f.id = m.family_id
+		WHERE `+where+`
+		GROUP BY day, f.specimen_type`, args...)
+	return counts, err
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Migrate applies, in file name order, every *.sql file of dir that has not
+// been applied yet. Each file runs in its own transaction and is recorded in
+// schema_migrations, so restarting the API is always safe.
+func Migrate(db *sqlx.DB, dir string) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    TEXT PRIMARY KEY,
+			applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`); err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	var applied []string
+	if err := db.Select(&applied, `SELECT version FROM schema_migrations`); err != nil {
+		return err
+	}
+	done := make(map[string]bool, len(applied))
+	for _, v := range applied {
+		done[v] = true
+	}
+
+	for _, file := range files {
+		version := strings.TrimSuffix(filepath.Base(file), ".sql")
+		if done[version] {
+			continue
+		}
+
+		script, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		tx, err := db.Beginx()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(string(script)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %s: %w", version, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, version); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		log.Printf("[Migrate] Applied %s", version)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"strings"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/family"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/project"
+	"github.com/jmoiron/sqlx"
+)
+
+func (p *projectRepository) QualityFamilies(filter project.QualityFilter) ([]family.Family, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.ClientID > 0 {
+		conditions = append(conditions, `project_id IN (SELECT id FROM projects WHERE client_id = ?)`)
+		args = append(args, filter.ClientID)
+	}
+	if filter.ProjectID > 0 {
+		conditions = append(conditions, `project_id = ?`)
+		args = append(args, filter.ProjectID)
+	}
+	if filter.DesignResistance > 0 {
+		conditions = append(conditions, `design_resistance = ?`)
+		args = append(args, filter.DesignResistance)
+	}
+	if filter.MixDesignCode != "" {
+		conditions = append(conditions, `mix_design_code = ?`)
+		args = append(args, filter.MixDesignCode)
+	}
+	if filter.Supplier != "" {
+		conditions = append(conditions, `supplier = ? COLLATE NOCASE`)
+		args = append(args, filter.Supplier)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, `substr(date_of_entry, 1, 10) >= ?`)
+		args = append(args, filter.From.Format("2006-01-02"))
+	}
+	if filter.To != nil {
+		conditions = append(conditions, `substr(date_of_entry, 1, 10) <= ?`)
+		args = append(args, filter.To.Format("2006-01-02"))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var families []family.Family
+	if err := p.db.Select(&families, `
+		SELECT `+familyColumns+`
+		FROM families`+where+`
+		ORDER BY id`, args...); err != nil {
+		return nil, err
+	}
+	if len(families) == 0 {
+		return families, nil
+	}
+
+	familyIDs := make([]int, len(families))
+	familyMap := make(map[int]*family.Family, len(families))
+	for i := range families {
+		familyIDs[i] = families[i].ID
+		familyMap[families[i].ID] = &families[i]
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT `+memberColumns+`
+		FROM members
+		WHERE family_id IN (?) AND result IS NOT NULL AND fracture_days = ?
+		ORDER BY id`, familyIDs, filter.AgeDays)
+	if err != nil {
+		return nil, err
+	}
+	var members []member.Member
+	if err := p.db.Select(&members, p.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		familyMap[m.FamilyID].Members = append(familyMap[m.FamilyID].Members, m)
+	}
+	return families, nil
+}
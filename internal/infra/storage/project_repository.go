@@ -1,9 +1,13 @@
 package storage
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"log"
+	"strings"
 
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/client"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/family"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
@@ -12,7 +16,11 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
-var PageSize int = 20
+const projectColumns = `p.id, p.name, p.client_id, p.address, p.city, p.latitude, p.longitude,
+        p.contractor, p.supervising_engineer, p.contract_number, p.start_date, p.end_date, p.status`
+
+// summaryColumns are the project columns of a project.Summary.
+const summaryColumns = `p.id, p.name, p.client_id, p.status, p.contractor, p.contract_number`
 
 type projectRepository struct {
 	db *sqlx.DB
@@ -28,10 +36,13 @@ func (p *projectRepository) GetProjectByID(ID int) (*project.Project, error) {
 	// -----------------------------
 	// 1. Project
 	// -----------------------------
-	projectRow := p.db.QueryRowx("SELECT id, name, client_id FROM projects WHERE id = ?", ID)
+	projectRow := p.db.QueryRowx("SELECT "+projectColumns+" FROM projects p WHERE p.id = ?", ID)
 	project := &project.Project{}
 	if err := projectRow.StructScan(project); err != nil {
 		log.Printf("[GetProjectByID] Project not found or scan failed. err=%v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("project %d: %w", ID, domain.ErrNotFound)
+		}
 		return nil, err
 	}
 	log.Printf("[GetProjectByID] Project loaded: %+v", project)
@@ -39,7 +50,7 @@ func (p *projectRepository) GetProjectByID(ID int) (*project.Project, error) {
 	// -----------------------------
 	// 2. Client
 	// -----------------------------
-	clientRow := p.db.QueryRowx("SELECT id, name FROM clients WHERE id = ?", project.ClientID)
+	clientRow := p.db.QueryRowx("SELECT "+clientColumns+" FROM clients WHERE id = ?", project.ClientID)
 	client := &client.Client{}
 	if err := clientRow.StructScan(client); err != nil {
 		log.Printf("[GetProjectByID] Failed loading client %d. err=%v", project.ClientID, err)
@@ -53,7 +64,7 @@ func (p *projectRepository) GetProjectByID(ID int) (*project.Project, error) {
 	// -----------------------------
 	var families []family.Family
 	if err := p.db.Select(&families, `
-        SELECT id, type, date_of_entry, radius, height, classification, client_id, project_id, design_resistance, sample_place
+        SELECT `+familyColumns+`
         FROM families
         WHERE project_id = ?`, project.ID); err != nil {
 		log.Printf("[GetProjectByID] Failed loading families. err=%v", err)
@@ -81,7 +92,7 @@ func (p *projectRepository) GetProjectByID(ID int) (*project.Project, error) {
 	// 4. Members
 	// -----------------------------
 	query, args, _ := sqlx.In(`
-        SELECT id, family_id, result, date_of_fracture, fractured_at, is_reported, operative, fracture_days, fracture_type
+        SELECT `+memberColumns+`
         FROM members
         WHERE family_id IN (?)`, familyIDs)
 	query = p.db.Rebind(query)
@@ -155,36 +166,30 @@ func (p *projectRepository) GetProjectByID(ID int) (*project.Project, error) {
 	return project, nil
 }
 
-func (p *projectRepository) GetProjects(page int) ([]*project.Project, error) {
-	log.Printf("[GetProjects] Starting. page=%d", page)
+func (p *projectRepository) GetProjects(filter project.ListFilter) ([]*project.Project, int, error) {
+	log.Printf("[GetProjects] Starting. filter=%+v", filter)
 
-	if page < 1 {
-		log.Printf("[GetProjects] Invalid page number: %d", page)
-		return nil, errors.New("page can't be less than one")
+	if filter.Page < 1 {
+		log.Printf("[GetProjects] Invalid page number: %d", filter.Page)
+		return nil, 0, errors.New("page can't be less than one")
 	}
 
-	offset := (page - 1) * PageSize
-	log.Printf("[GetProjects] PageSize=%d offset=%d", PageSize, offset)
+	log.Printf("[GetProjects] PageSize=%d offset=%d", filter.PageSize, filter.Offset())
 
 	// ---------------------------
 	// 1. Proyectos base
 	// ---------------------------
 	var projects []project.Project
-	err := p.db.Select(&projects, `
-        SELECT id, name, client_id
-        FROM projects
-        ORDER BY id
-        LIMIT ? OFFSET ?`,
-		PageSize, offset)
+	total, err := p.selectProjectPage(&projects, projectColumns, filter)
 	if err != nil {
 		log.Printf("[GetProjects] Failed loading projects. err=%v", err)
-		return nil, err
+		return nil, 0, err
 	}
 	log.Printf("[GetProjects] Projects found=%d", len(projects))
 
 	if len(projects) == 0 {
 		log.Printf("[GetProjects] No projects in this page.")
-		return []*project.Project{}, nil
+		return []*project.Project{}, total, nil
 	}
 
 	projMap := make(map[int]*project.Project)
@@ -207,19 +212,19 @@ func (p *projectRepository) GetProjects(page int) ([]*project.Project, error) {
 	log.Printf("[GetProjects] Client IDs=%v", clientIDs)
 
 	query, args, err := sqlx.In(`
-        SELECT id, name
+        SELECT `+clientColumns+`
         FROM clients
         WHERE id IN (?)`, clientIDs)
 	if err != nil {
 		log.Printf("[GetProjects] sqlx.In error clients. err=%v", err)
-		return nil, err
+		return nil, 0, err
 	}
 	query = p.db.Rebind(query)
 
 	var clients []client.Client
 	if err := p.db.Select(&clients, query, args...); err != nil {
 		log.Printf("[GetProjects] Failed loading clients. err=%v", err)
-		return nil, err
+		return nil, 0, err
 	}
 	log.Printf("[GetProjects] Clients found=%d", len(clients))
 
@@ -237,19 +242,19 @@ func (p *projectRepository) GetProjects(page int) ([]*project.Project, error) {
 	// 3. Familias
 	// ---------------------------
 	query, args, err = sqlx.In(`
-        SELECT id, type, date_of_entry, radius, height, classification, client_id, project_id
+        SELECT `+familyColumns+`
         FROM families
         WHERE project_id IN (?)`, projectIDs)
 	if err != nil {
 		log.Printf("[GetProjects] sqlx.In error families. err=%v", err)
-		return nil, err
+		return nil, 0, err
 	}
 	query = p.db.Rebind(query)
 
 	var families []family.Family
 	if err := p.db.Select(&families, query, args...); err != nil {
 		log.Printf("[GetProjects] Failed loading families. err=%v", err)
-		return nil, err
+		return nil, 0, err
 	}
 	log.Printf("[GetProjects] Families found=%d", len(families))
 
@@ -271,7 +276,7 @@ func (p *projectRepository) GetProjects(page int) ([]*project.Project, error) {
 		for i := range projects {
 			out = append(out, &projects[i])
 		}
-		return out, nil
+		return out, total, nil
 	}
 
 	// ---------------------------
@@ -283,14 +288,14 @@ func (p *projectRepository) GetProjects(page int) ([]*project.Project, error) {
         WHERE family_id IN (?)`, familyIDs)
 	if err != nil {
 		log.Printf("[GetProjects] sqlx.In error members. err=%v", err)
-		return nil, err
+		return nil, 0, err
 	}
 	query = p.db.Rebind(query)
 
 	var members []member.Member
 	if err := p.db.Select(&members, query, args...); err != nil {
 		log.Printf("[GetProjects] Failed loading members. err=%v", err)
-		return nil, err
+		return nil, 0, err
 	}
 	log.Printf("[GetProjects] Members found=%d", len(members))
 
@@ -314,14 +319,51 @@ func (p *projectRepository) GetProjects(page int) ([]*project.Project, error) {
 	}
 	log.Printf("[GetProjects] Returning %d projects", len(out))
 
-	return out, nil
+	return out, total, nil
+}
+
+// selectProjectPage loads the given columns of the page of projects matching
+// the filter into dest and returns how many projects match in total.
+func (p *projectRepository) selectProjectPage(dest interface{}, columns string, filter project.ListFilter) (int, error) {
+	where, args := projectFilterClauses(filter)
+
+	var total int
+	if err := p.db.Get(&total, `
+        SELECT COUNT(*)
+        FROM projects p
+        JOIN clients c ON c.id = p.client_id`+where, args...); err != nil {
+		return 0, err
+	}
+
+	if afterID := filter.AfterID(); afterID > 0 {
+		keyset := "p.id > ?"
+		if filter.Descending() {
+			keyset = "p.id < ?"
+		}
+		where = appendCondition(where, keyset)
+		args = append(args, afterID)
+	}
+
+	query := `
+        SELECT ` + columns + `
+        FROM projects p
+        JOIN clients c ON c.id = p.client_id` + where + projectOrderBy(filter) + `
+        LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit(), filter.Offset())
+
+	if err := p.db.Select(dest, query, args...); err != nil {
+		return 0, err
+	}
+	return total, nil
 }
 
 func (r *projectRepository) SaveProject(p *project.Project) (*project.Project, error) {
 	result, err := r.db.Exec(`
-        INSERT INTO projects (name, client_id)
-        VALUES (?, ?)
-    `, p.Name, p.ClientID)
+        INSERT INTO projects (name, client_id, address, city, latitude, longitude,
+            contractor, supervising_engineer, contract_number, start_date, end_date, status)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `, p.Name, p.ClientID, p.Address, p.City, p.Latitude, p.Longitude,
+		p.Contractor, p.SupervisingEngineer, p.ContractNumber, p.StartDate, p.EndDate, p.Status)
 	log.Printf("%+v", p)
 
 	if err != nil {
@@ -343,143 +385,162 @@ func (r *projectRepository) SaveProject(p *project.Project) (*project.Project, e
 	return created, nil
 }
 
-func (p *projectRepository) GetProjectsByClientID(clientID int) ([]*project.Project, error) {
-	log.Printf("[GetProjectsByClientID] Starting. clientID=%d", clientID)
+func (r *projectRepository) UpdateProject(p *project.Project) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+        UPDATE projects
+        SET name = ?, client_id = ?, address = ?, city = ?, latitude = ?, longitude = ?,
+            contractor = ?, supervising_engineer = ?, contract_number = ?,
+            start_date = ?, end_date = ?, status = ?
+        WHERE id = ?`,
+		p.Name, p.ClientID, p.Address, p.City, p.Latitude, p.Longitude,
+		p.Contractor, p.SupervisingEngineer, p.ContractNumber,
+		p.StartDate, p.EndDate, p.Status, p.ID); err != nil {
+		return err
+	}
+	// A family always belongs to the client of its project.
+	if _, err := tx.Exec(`UPDATE families SET client_id = ? WHERE project_id = ? AND client_id IS NOT ?`,
+		p.ClientID, p.ID, p.ClientID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
 
-	if clientID < 1 {
-		log.Printf("[GetProjectsByClientID] Invalid clientID=%d", clientID)
-		return nil, errors.New("clientID must be greater than zero")
+func (p *projectRepository) ClientExists(clientID int) (bool, error) {
+	var exists bool
+	err := p.db.Get(&exists, `SELECT EXISTS(SELECT 1 FROM clients WHERE id = ?)`, clientID)
+	if err != nil {
+		return false, err
 	}
+	return exists, nil
+}
 
-	// ---------------------------
-	// 1. Proyectos base
-	// ---------------------------
-	var projects []project.Project
-	err := p.db.Select(&projects, `
-        SELECT id, name, client_id
-        FROM projects
-        WHERE client_id = ?
-        ORDER BY id`, clientID)
-
-	if err != nil {
-		log.Printf("[GetProjectsByClientID] Failed loading projects. err=%v", err)
+func (p *projectRepository) FractureTypeCounts(projectID int) (map[string]int, error) {
+	var exists bool
+	if err := p.db.Get(&exists, `SELECT EXISTS(SELECT 1 FROM projects WHERE id = ?)`, projectID); err != nil {
 		return nil, err
 	}
-
-	log.Printf("[GetProjectsByClientID] Projects found=%d", len(projects))
-
-	if len(projects) == 0 {
-		return []*project.Project{}, nil
+	if !exists {
+		return nil, fmt.Errorf("project %d: %w", projectID, domain.ErrNotFound)
 	}
 
-	projMap := make(map[int]*project.Project)
-	projectIDs := make([]int, 0, len(projects))
-
-	for i := range projects {
-		proj := &projects[i]
-		projMap[proj.ID] = proj
-		projectIDs = append(projectIDs, proj.ID)
+	var rows []struct {
+		FractureType string `db:"fracture_type"`
+		Count        int    `db:"count"`
 	}
-
-	// ---------------------------
-	// 2. Cargar el cliente
-	// ---------------------------
-	var cli client.Client
-	err = p.db.Get(&cli, `SELECT id, name FROM clients WHERE id = ?`, clientID)
+	err := p.db.Select(&rows, `
+        SELECT COALESCE(m.fracture_type, '') AS fracture_type, COUNT(*) AS count
+        FROM members m
+        JOIN families f ON f.id = m.family_id
+        WHERE f.project_id = ? AND m.result IS NOT NULL
+        GROUP BY COALESCE(m.fracture_type, '')`, projectID)
 	if err != nil {
-		log.Printf("[GetProjectsByClientID] Failed loading client. err=%v", err)
 		return nil, err
 	}
 
-	// Asignar cliente a cada proyecto
-	for _, p := range projMap {
-		p.Client = cli
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.FractureType] = row.Count
 	}
+	return counts, nil
+}
 
-	// ---------------------------
-	// 3. Familias
-	// ---------------------------
-	query, args, err := sqlx.In(`
-        SELECT id, type, date_of_entry, radius, height, classification, client_id, project_id, sample_place
-        FROM families
-        WHERE project_id IN (?)`, projectIDs)
-	if err != nil {
-		log.Printf("[GetProjectsByClientID] sqlx.In error families. err=%v", err)
-		return nil, err
-	}
+// pendingMemberExists matches projects that still have members waiting to be
+// reported.
+const pendingMemberExists = `
+        EXISTS (
+            SELECT 1 FROM members m
+            JOIN families f ON f.id = m.family_id
+            WHERE f.project_id = p.id AND COALESCE(m.is_reported, 0) = 0)`
 
-	query = p.db.Rebind(query)
+// projectFilterClauses translates a project.ListFilter into a WHERE clause
+// over "projects p JOIN clients c" plus its arguments.
+func projectFilterClauses(filter project.ListFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
 
-	var families []family.Family
-	if err := p.db.Select(&families, query, args...); err != nil {
-		log.Printf("[GetProjectsByClientID] Failed loading families. err=%v", err)
-		return nil, err
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		like := "%" + escapeLike(search) + "%"
+		conditions = append(conditions, `(p.name LIKE ? ESCAPE '\' OR c.name LIKE ? ESCAPE '\' OR p.contract_number LIKE ? ESCAPE '\')`)
+		args = append(args, like, like, like)
 	}
 
-	log.Printf("[GetProjectsByClientID] Families found=%d", len(families))
-
-	famMap := make(map[int]*family.Family)
-	familyIDs := make([]int, 0, len(families))
+	if filter.ProjectStatus != "" {
+		conditions = append(conditions, "p.status = ?")
+		args = append(args, filter.ProjectStatus)
+	}
 
-	for i := range families {
-		f := &families[i]
-		famMap[f.ID] = f
-		familyIDs = append(familyIDs, f.ID)
+	if contractor := strings.TrimSpace(filter.Contractor); contractor != "" {
+		conditions = append(conditions, `p.contractor LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(contractor)+"%")
+	}
 
-		projMap[f.ProjectID].Families = append(projMap[f.ProjectID].Families, *f)
+	if filter.ClientID > 0 {
+		conditions = append(conditions, "p.client_id = ?")
+		args = append(args, filter.ClientID)
 	}
 
-	if len(families) == 0 {
-		// No hay familias → retornar proyectos tal cual
-		out := make([]*project.Project, 0, len(projects))
-		for i := range projects {
-			out = append(out, &projects[i])
+	if filter.EntryFrom != nil || filter.EntryTo != nil {
+		// date_of_entry is stored as text starting with YYYY-MM-DD, so the
+		// day prefix compares correctly as a string.
+		familyCond := "f.project_id = p.id"
+		if filter.EntryFrom != nil {
+			familyCond += " AND substr(f.date_of_entry, 1, 10) >= ?"
+			args = append(args, filter.EntryFrom.Format("2006-01-02"))
+		}
+		if filter.EntryTo != nil {
+			familyCond += " AND substr(f.date_of_entry, 1, 10) <= ?"
+			args = append(args, filter.EntryTo.Format("2006-01-02"))
 		}
-		return out, nil
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM families f WHERE "+familyCond+")")
 	}
 
-	// ---------------------------
-	// 4. Miembros
-	// ---------------------------
-	query, args, err = sqlx.In(`
-        SELECT id, family_id, result, date_of_fracture, is_reported
-        FROM members
-        WHERE family_id IN (?)`, familyIDs)
-	if err != nil {
-		log.Printf("[GetProjectsByClientID] sqlx.In error members. err=%v", err)
-		return nil, err
+	switch filter.Status {
+	case project.StatusPending:
+		conditions = append(conditions, pendingMemberExists)
+	case project.StatusReported:
+		conditions = append(conditions, `NOT`+pendingMemberExists+`
+        AND EXISTS (
+            SELECT 1 FROM members m
+            JOIN families f ON f.id = m.family_id
+            WHERE f.project_id = p.id)`)
 	}
 
-	query = p.db.Rebind(query)
-
-	var members []member.Member
-	if err := p.db.Select(&members, query, args...); err != nil {
-		log.Printf("[GetProjectsByClientID] Failed loading members. err=%v", err)
-		return nil, err
+	if len(conditions) == 0 {
+		return "", args
 	}
+	return "\n        WHERE " + strings.Join(conditions, "\n        AND "), args
+}
 
-	log.Printf("[GetProjectsByClientID] Members found=%d", len(members))
-
-	for _, m := range members {
-		famMap[m.FamilyID].Members = append(famMap[m.FamilyID].Members, m)
+// appendCondition adds one more condition to a clause built by
+// projectFilterClauses.
+func appendCondition(where, condition string) string {
+	if where == "" {
+		return "\n        WHERE " + condition
 	}
+	return where + "\n        AND " + condition
+}
 
-	// Reconstruir estructuras anidadas
-	for projID := range projMap {
-		for i := range projMap[projID].Families {
-			famID := projMap[projID].Families[i].ID
-			projMap[projID].Families[i] = *famMap[famID]
-		}
+func projectOrderBy(filter project.ListFilter) string {
+	direction := "ASC"
+	if filter.Descending() {
+		direction = "DESC"
 	}
 
-	// ---------------------------
-	// Final
-	// ---------------------------
-	out := make([]*project.Project, 0, len(projects))
-	for i := range projects {
-		out = append(out, &projects[i])
+	// Ids are autoincrement, so they follow creation order.
+	switch filter.SortField() {
+	case project.SortName:
+		return "\n        ORDER BY p.name COLLATE NOCASE " + direction + ", p.id " + direction
+	default:
+		return "\n        ORDER BY p.id " + direction
 	}
+}
 
-	log.Printf("[GetProjectsByClientID] Returning %d projects", len(out))
-	return out, nil
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
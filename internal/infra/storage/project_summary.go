@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"log"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/client"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/family"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/member"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/project"
+	"github.com/jmoiron/sqlx"
+)
+
+type familyAggregate struct {
+	ProjectID   int    `db:"project_id"`
+	FamilyCount int    `db:"family_count"`
+	LastEntry   string `db:"last_entry"`
+}
+
+type memberAggregate struct {
+	ProjectID      int    `db:"project_id"`
+	PendingCount   int    `db:"pending_count"`
+	FracturedCount int    `db:"fractured_count"`
+	ReportedCount  int    `db:"reported_count"`
+	LastFracture   string `db:"last_fracture"`
+}
+
+func (p *projectRepository) GetProjectSummaries(filter project.ListFilter) ([]*project.Summary, int, error) {
+	log.Printf("[GetProjectSummaries] Starting. filter=%+v", filter)
+
+	// ---------------------------
+	// 1. Proyectos base
+	// ---------------------------
+	var summaries []*project.Summary
+	total, err := p.selectProjectPage(&summaries, summaryColumns, filter)
+	if err != nil {
+		log.Printf("[GetProjectSummaries] Failed loading projects. err=%v", err)
+		return nil, 0, err
+	}
+	if len(summaries) == 0 {
+		return summaries, total, nil
+	}
+
+	projectIDs := make([]int, 0, len(summaries))
+	clientIDs := make([]int, 0, len(summaries))
+	byID := make(map[int]*project.Summary, len(summaries))
+	for _, s := range summaries {
+		projectIDs = append(projectIDs, s.ID)
+		clientIDs = append(clientIDs, s.ClientID)
+		byID[s.ID] = s
+	}
+
+	// ---------------------------
+	// 2. Clientes
+	// ---------------------------
+	query, args, err := sqlx.In(`SELECT `+clientColumns+` FROM clients WHERE id IN (?)`, clientIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	var clients []client.Client
+	if err := p.db.Select(&clients, p.db.Rebind(query), args...); err != nil {
+		log.Printf("[GetProjectSummaries] Failed loading clients. err=%v", err)
+		return nil, 0, err
+	}
+	clientMap := make(map[int]client.Client, len(clients))
+	for _, c := range clients {
+		clientMap[c.ID] = c
+	}
+	for _, s := range summaries {
+		s.Client = clientMap[s.ClientID]
+	}
+
+	// ---------------------------
+	// 3. Agregados de familias
+	// ---------------------------
+	query, args, err = sqlx.In(`
+        SELECT project_id,
+               COUNT(*) AS family_count,
+               COALESCE(MAX(substr(date_of_entry, 1, 10)), '') AS last_entry
+        FROM families
+        WHERE project_id IN (?)
+        GROUP BY project_id`, projectIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	var families []familyAggregate
+	if err := p.db.Select(&families, p.db.Rebind(query), args...); err != nil {
+		log.Printf("[GetProjectSummaries] Failed aggregating families. err=%v", err)
+		return nil, 0, err
+	}
+	lastActivity := make(map[int]string, len(summaries))
+	for _, f := range families {
+		byID[f.ProjectID].FamilyCount = f.FamilyCount
+		lastActivity[f.ProjectID] = f.LastEntry
+	}
+
+	// ---------------------------
+	// 4. Agregados de miembros
+	// ---------------------------
+	query, args, err = sqlx.In(`
+        SELECT f.project_id,
+               SUM(CASE WHEN m.result IS NULL THEN 1 ELSE 0 END) AS pending_count,
+               SUM(CASE WHEN m.result IS NOT NULL THEN 1 ELSE 0 END) AS fractured_count,
+               SUM(CASE WHEN COALESCE(m.is_reported, 0) = 1 THEN 1 ELSE 0 END) AS reported_count,
+               COALESCE(MAX(substr(m.fractured_at, 1, 10)), '') AS last_fracture
+        FROM members m
+        JOIN families f ON f.id = m.family_id
+        WHERE f.project_id IN (?)
+        GROUP BY f.project_id`, projectIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	var members []memberAggregate
+	if err := p.db.Select(&members, p.db.Rebind(query), args...); err != nil {
+		log.Printf("[GetProjectSummaries] Failed aggregating members. err=%v", err)
+		return nil, 0, err
+	}
+	for _, m := range members {
+		s := byID[m.ProjectID]
+		s.PendingCount = m.PendingCount
+		s.FracturedCount = m.FracturedCount
+		s.ReportedCount = m.ReportedCount
+		// Ambas fechas vienen como YYYY-MM-DD, así que se comparan como texto.
+		if m.LastFracture > lastActivity[m.ProjectID] {
+			lastActivity[m.ProjectID] = m.LastFracture
+		}
+	}
+
+	// ---------------------------
+	// 5. Cumplimiento
+	// ---------------------------
+	if err := p.loadCompliance(projectIDs, byID); err != nil {
+		log.Printf("[GetProjectSummaries] Failed computing compliance. err=%v", err)
+		return nil, 0, err
+	}
+
+	for id, day := range lastActivity {
+		if day == "" {
+			continue
+		}
+		if err := byID[id].LastActivity.Scan(day); err != nil {
+			log.Printf("[GetProjectSummaries] Invalid activity date %q. err=%v", day, err)
+		}
+	}
+
+	log.Printf("[GetProjectSummaries] Returning %d summaries", len(summaries))
+	return summaries, total, nil
+}
+
+// loadCompliance sets the compliance of each summary from family.ComputeStats
+// over the members broken at family.AcceptanceAgeDays or later, so the list
+// agrees with family stats and reports.
+func (p *projectRepository) loadCompliance(projectIDs []int, byID map[int]*project.Summary) error {
+	query, args, err := sqlx.In(`SELECT `+familyColumns+` FROM families WHERE project_id IN (?)`, projectIDs)
+	if err != nil {
+		return err
+	}
+	var families []family.Family
+	if err := p.db.Select(&families, p.db.Rebind(query), args...); err != nil {
+		return err
+	}
+	if len(families) == 0 {
+		return nil
+	}
+
+	familyIDs := make([]int, len(families))
+	familyMap := make(map[int]*family.Family, len(families))
+	for i := range families {
+		familyIDs[i] = families[i].ID
+		familyMap[families[i].ID] = &families[i]
+	}
+
+	query, args, err = sqlx.In(`
+		SELECT `+memberColumns+`
+		FROM members
+		WHERE family_id IN (?) AND result IS NOT NULL AND fracture_days >= ?`, familyIDs, family.AcceptanceAgeDays)
+	if err != nil {
+		return err
+	}
+	var members []member.Member
+	if err := p.db.Select(&members, p.db.Rebind(query), args...); err != nil {
+		return err
+	}
+	for _, m := range members {
+		familyMap[m.FamilyID].Members = append(familyMap[m.FamilyID].Members, m)
+	}
+
+	atAcceptance := make(map[int]int, len(byID))
+	compliant := make(map[int]int, len(byID))
+	for i := range families {
+		f := &families[i]
+		f.ComputeStats()
+		atAcceptance[f.ProjectID] += f.Stats.AcceptanceCount
+		compliant[f.ProjectID] += f.Stats.CompliantCount
+	}
+	for id, count := range atAcceptance {
+		if count > 0 {
+			compliance := float64(compliant[id]) / float64(count) * 100
+			byID[id].Compliance = &compliance
+		}
+	}
+	return nil
+}
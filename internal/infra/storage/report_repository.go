@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/report"
+	"github.com/jmoiron/sqlx"
+)
+
+type reportRepository struct {
+	db *sqlx.DB
+}
+
+func NewReportRepository(db *sqlx.DB) report.Repository {
+	return &reportRepository{db: db}
+}
+
+func (r *reportRepository) SaveIssued(i *report.Issued) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		INSERT INTO issued_reports (project_id, family_id, filename, issued_by, issued_at, file)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		i.ProjectID, i.FamilyID, i.Filename, i.IssuedBy, i.IssuedAt, i.File)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	i.ID = int(id)
+
+	for k := range i.Recipients {
+		rcpt := &i.Recipients[k]
+		rcpt.IssuedReportID = i.ID
+		rcpt.Status = report.DeliveryPending
+		if _, err := tx.Exec(`
+			INSERT INTO issued_report_recipients (issued_report_id, contact_id, name, email)
+			VALUES (?, ?, ?, ?)`,
+			rcpt.IssuedReportID, rcpt.ContactID, rcpt.Name, rcpt.Email); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *reportRepository) GetIssued(familyID int, req domain.PageRequest) ([]*report.Issued, int, error) {
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM issued_reports WHERE family_id = ?`, familyID); err != nil {
+		return nil, 0, err
+	}
+
+	args := []interface{}{familyID}
+	keyset := ""
+	if afterID := req.AfterID(); afterID > 0 {
+		keyset = " AND id < ?"
+		args = append(args, afterID)
+	}
+	args = append(args, req.Limit(), req.Offset())
+
+	issued := []*report.Issued{}
+	err := r.db.Select(&issued, `
+		SELECT `+issuedColumns+`
+		FROM issued_reports
+		WHERE family_id = ?`+keyset+`
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadRecipients(issued); err != nil {
+		return nil, 0, err
+	}
+	return issued, total, nil
+}
+
+const issuedColumns = `id, project_id, family_id, filename, issued_by, issued_at,
+		       superseded_at IS NOT NULL AS superseded, superseded_at, superseded_by_correction_id`
+
+func (r *reportRepository) GetIssuedByID(ID int) (*report.Issued, error) {
+	i := &report.Issued{}
+	err := r.db.Get(i, `SELECT `+issuedColumns+` FROM issued_reports WHERE id = ?`, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("issued report %d: %w", ID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadRecipients([]*report.Issued{i}); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (r *reportRepository) GetIssuedFile(ID int) ([]byte, error) {
+	var file []byte
+	err := r.db.Get(&file, `SELECT file FROM issued_reports WHERE id = ?`, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("issued report %d: %w", ID, domain.ErrNotFound)
+	}
+	return file, err
+}
+
+func (r *reportRepository) RecordDelivery(ID int, email string, sendErr error, at time.Time) error {
+	if sendErr != nil {
+		_, err := r.db.Exec(`
+			UPDATE issued_report_recipients
+			SET status = ?, attempts = attempts + 1, last_error = ?, last_attempt_at = ?
+			WHERE issued_report_id = ? AND email = ?`,
+			report.DeliveryFailed, sendErr.Error(), at, ID, email)
+		return err
+	}
+	_, err := r.db.Exec(`
+		UPDATE issued_report_recipients
+		SET status = ?, attempts = attempts + 1, last_error = NULL, last_attempt_at = ?, sent_at = ?
+		WHERE issued_report_id = ? AND email = ?`,
+		report.DeliverySent, at, at, ID, email)
+	return err
+}
+
+// loadRecipients fills in the recipients of the given reports.
+func (r *reportRepository) loadRecipients(issued []*report.Issued) error {
+	if len(issued) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(issued))
+	byID := make(map[int]*report.Issued, len(issued))
+	for k, i := range issued {
+		i.Recipients = []report.Recipient{}
+		ids[k] = i.ID
+		byID[i.ID] = i
+	}
+
+	query, qargs, err := sqlx.In(`
+		SELECT issued_report_id, contact_id, name, email,
+		       status, attempts, last_error, last_attempt_at, sent_at
+		FROM issued_report_recipients
+		WHERE issued_report_id IN (?)
+		ORDER BY rowid`, ids)
+	if err != nil {
+		return err
+	}
+	var recipients []report.Recipient
+	if err := r.db.Select(&recipients, r.db.Rebind(query), qargs...); err != nil {
+		return err
+	}
+	for _, rcpt := range recipients {
+		i := byID[rcpt.IssuedReportID]
+		i.Recipients = append(i.Recipients, rcpt)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"html"
+	"strings"
+
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/search"
+	"github.com/jmoiron/sqlx"
+)
+
+type searchRepository struct {
+	db *sqlx.DB
+}
+
+func NewSearchRepository(db *sqlx.DB) search.Repository {
+	return &searchRepository{db: db}
+}
+
+func (r *searchRepository) Search(terms []string, limit int) ([]*search.Hit, error) {
+	// Terms only contain letters and digits, quoting them keeps FTS5 from
+	// reading words such as AND/OR/NEAR as operators.
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = `"` + t + `"*`
+	}
+
+	hits := []*search.Hit{}
+	err := r.db.Select(&hits, `
+		SELECT kind, entity_id, title,
+		       highlight(search_index, 0, ?, ?) AS highlight,
+		       project_id, client_id
+		FROM search_index
+		WHERE search_index MATCH ?
+		ORDER BY rank
+		LIMIT ?`, markOpen, markClose, strings.Join(quoted, " "), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, h := range hits {
+		h.Highlight = markHighlight(h.Highlight)
+	}
+	return hits, nil
+}
+
+// FTS5 wraps the matches in these private use characters, which never show
+// up in names, so the text can be escaped before the <mark> tags go in.
+const (
+	markOpen  = "\ue000"
+	markClose = "\ue001"
+)
+
+// markHighlight HTML-escapes the highlighted title and turns the markers
+// into <mark> tags: names are user input and must not come back as markup.
+func markHighlight(s string) string {
+	s = html.EscapeString(s)
+	return strings.NewReplacer(markOpen, "<mark>", markClose, "</mark>").Replace(s)
+}
@@ -1,8 +1,13 @@
 package storage
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"time"
 
+	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain"
 	"github.com/AugustoGuapo/concretrack-backoffice-be/internal/domain/user"
 	"github.com/jmoiron/sqlx"
 )
@@ -26,3 +31,89 @@ func (r *userRepository) GetByUsername(username string) (*user.User, error) {
 
 	return u, nil
 }
+
+func (r *userRepository) GetUsers(req domain.PageRequest) ([]*user.User, int, error) {
+	var total int
+	if err := r.db.Get(&total, "SELECT COUNT(*) FROM users"); err != nil {
+		return nil, 0, err
+	}
+
+	var users []*user.User
+	err := r.db.Select(&users, `
+		SELECT id, username, first_name, last_name, role, is_active
+		FROM users
+		WHERE id > ?
+		ORDER BY id
+		LIMIT ? OFFSET ?`,
+		req.AfterID(), req.Limit(), req.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func (r *userRepository) GetByID(ID int) (*user.User, error) {
+	u := &user.User{}
+	err := r.db.Get(u, "SELECT id, username, first_name, last_name, role, is_active FROM users WHERE id = ?", ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("user %d: %w", ID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (r *userRepository) UsernameTaken(username string, exceptID int) (bool, error) {
+	var taken bool
+	err := r.db.Get(&taken, "SELECT EXISTS (SELECT 1 FROM users WHERE username = ? AND id <> ?)", username, exceptID)
+	return taken, err
+}
+
+func (r *userRepository) CreateUser(u *user.User) error {
+	res, err := r.db.Exec(`
+		INSERT INTO users (username, first_name, last_name, role, password, is_active)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		u.Username, u.FirstName, u.LastName, u.Role, u.Password, u.IsActive)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	u.ID = int(id)
+	return nil
+}
+
+func (r *userRepository) UpdateUser(u *user.User) error {
+	_, err := r.db.Exec(`
+		UPDATE users
+		SET first_name = ?, last_name = ?, role = ?, is_active = ?,
+		    password = CASE WHEN ? = '' THEN password ELSE ? END
+		WHERE id = ?`,
+		u.FirstName, u.LastName, u.Role, u.IsActive, u.Password, u.Password, u.ID)
+	return err
+}
+
+func (r *userRepository) CreateSession(userID int, tokenHash string, expiresAt time.Time) error {
+	_, err := r.db.Exec(`
+		INSERT INTO sessions (token_hash, user_id, expires_at)
+		VALUES (?, ?, ?)`, tokenHash, userID, expiresAt.UTC().Format(time.RFC3339))
+	return err
+}
+
+func (r *userRepository) GetBySessionToken(tokenHash string, now time.Time) (*user.User, error) {
+	u := &user.User{}
+	err := r.db.Get(u, `
+		SELECT u.id, u.username, u.first_name, u.last_name, u.role, u.is_active
+		FROM sessions s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.token_hash = ? AND s.expires_at > ? AND u.is_active = 1`,
+		tokenHash, now.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
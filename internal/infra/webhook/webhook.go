@@ -0,0 +1,48 @@
+// Package webhook posts JSON events to external URLs.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// timeout bounds each call, whatever the context allows.
+const timeout = 15 * time.Second
+
+// Poster sends each payload as a JSON POST. Any 2xx answer is a success.
+type Poster struct {
+	client *http.Client
+}
+
+func NewPoster() *Poster {
+	return &Poster{client: &http.Client{Timeout: timeout}}
+}
+
+func (p *Poster) Post(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "concretrack-webhook")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+-- Full-text index over the names staff use to find things: client names,
+-- project names and family sample places. Triggers keep it in sync.
+CREATE VIRTUAL TABLE search_index USING fts5(
+    title,
+    kind UNINDEXED,
+    entity_id UNINDEXED,
+    project_id UNINDEXED,
+    client_id UNINDEXED,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO search_index (title, kind, entity_id, project_id, client_id)
+SELECT name, 'client', id, NULL, id FROM clients;
+
+INSERT INTO search_index (title, kind, entity_id, project_id, client_id)
+SELECT name, 'project', id, id, client_id FROM projects;
+
+INSERT INTO search_index (title, kind, entity_id, project_id, client_id)
+SELECT sample_place, 'family', id, project_id, client_id FROM families
+WHERE sample_place IS NOT NULL AND sample_place <> '';
+
+-- clients
+CREATE TRIGGER search_clients_ai AFTER INSERT ON clients BEGIN
+    INSERT INTO search_index (title, kind, entity_id, project_id, client_id)
+    VALUES (new.name, 'client', new.id, NULL, new.id);
+END;
+
+CREATE TRIGGER search_clients_au AFTER UPDATE OF name ON clients BEGIN
+    DELETE FROM search_index WHERE kind = 'client' AND entity_id = old.id;
+    INSERT INTO search_index (title, kind, entity_id, project_id, client_id)
+    VALUES (new.name, 'client', new.id, NULL, new.id);
+END;
+
+CREATE TRIGGER search_clients_ad AFTER DELETE ON clients BEGIN
+    DELETE FROM search_index WHERE kind = 'client' AND entity_id = old.id;
+END;
+
+-- projects
+CREATE TRIGGER search_projects_ai AFTER INSERT ON projects BEGIN
+    INSERT INTO search_index (title, kind, entity_id, project_id, client_id)
+    VALUES (new.name, 'project', new.id, new.id, new.client_id);
+END;
+
+CREATE TRIGGER search_projects_au AFTER UPDATE OF name, client_id ON projects BEGIN
+    DELETE FROM search_index WHERE kind = 'project' AND entity_id = old.id;
+    INSERT INTO search_index (title, kind, entity_id, project_id, client_id)
+    VALUES (new.name, 'project', new.id, new.id, new.client_id);
+END;
+
+CREATE TRIGGER search_projects_ad AFTER DELETE ON projects BEGIN
+    DELETE FROM search_index WHERE kind = 'project' AND entity_id = old.id;
+END;
+
+-- families
+CREATE TRIGGER search_families_ai AFTER INSERT ON families
+WHEN new.sample_place IS NOT NULL AND new.sample_place <> '' BEGIN
+    INSERT INTO search_index (title, kind, entity_id, project_id, client_id)
+    VALUES (new.sample_place, 'family', new.id, new.project_id, new.client_id);
+END;
+
+CREATE TRIGGER search_families_au AFTER UPDATE OF sample_place, project_id, client_id ON families BEGIN
+    DELETE FROM search_index WHERE kind = 'family' AND entity_id = old.id;
+    INSERT INTO search_index (title, kind, entity_id, project_id, client_id)
+    SELECT new.sample_place, 'family', new.id, new.project_id, new.client_id
+    WHERE new.sample_place IS NOT NULL AND new.sample_place <> '';
+END;
+
+CREATE TRIGGER search_families_ad AFTER DELETE ON families BEGIN
+    DELETE FROM search_index WHERE kind = 'family' AND entity_id = old.id;
+END;
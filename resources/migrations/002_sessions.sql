@@ -0,0 +1,9 @@
+-- Bearer tokens issued at login. Only the SHA-256 of the token is stored.
+CREATE TABLE sessions (
+    token_hash TEXT PRIMARY KEY,
+    user_id    INTEGER NOT NULL REFERENCES users(id),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TEXT NOT NULL
+);
+
+CREATE INDEX sessions_user_id ON sessions (user_id);
@@ -0,0 +1,16 @@
+-- Trail of data changes: who, when, and the before/after values.
+CREATE TABLE audit_log (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity      TEXT NOT NULL,
+    entity_id   INTEGER NOT NULL,
+    action      TEXT NOT NULL,
+    user_id     INTEGER REFERENCES users(id),
+    username    TEXT,
+    created_at  DATETIME NOT NULL,
+    before_json TEXT,
+    after_json  TEXT,
+    diff_json   TEXT
+);
+
+CREATE INDEX audit_log_entity ON audit_log (entity, entity_id);
+CREATE INDEX audit_log_user_id ON audit_log (user_id);
@@ -0,0 +1,33 @@
+-- Corrections to a recorded fracture result. The member keeps its value
+-- until a lab manager approves the correction.
+CREATE TABLE member_corrections (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    member_id       INTEGER NOT NULL REFERENCES members(id),
+    previous_result REAL,
+    proposed_result REAL NOT NULL,
+    reason          TEXT NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending',
+    requested_by    INTEGER NOT NULL REFERENCES users(id),
+    requested_at    DATETIME NOT NULL,
+    reviewed_by     INTEGER REFERENCES users(id),
+    reviewed_at     DATETIME,
+    review_note     TEXT
+);
+
+CREATE INDEX member_corrections_member_id ON member_corrections (member_id);
+CREATE UNIQUE INDEX member_corrections_one_pending ON member_corrections (member_id) WHERE status = 'pending';
+
+-- Every report handed out for a family, so a later correction can flag it
+-- as superseded.
+CREATE TABLE issued_reports (
+    id                          INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id                  INTEGER NOT NULL REFERENCES projects(id),
+    family_id                   INTEGER NOT NULL REFERENCES families(id),
+    filename                    TEXT NOT NULL,
+    issued_by                   INTEGER REFERENCES users(id),
+    issued_at                   DATETIME NOT NULL,
+    superseded_at               DATETIME,
+    superseded_by_correction_id INTEGER REFERENCES member_corrections(id)
+);
+
+CREATE INDEX issued_reports_family_id ON issued_reports (family_id);
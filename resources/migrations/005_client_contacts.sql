@@ -0,0 +1,28 @@
+-- Identification and address of the client, printed on reports.
+ALTER TABLE clients ADD COLUMN tax_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE clients ADD COLUMN address TEXT NOT NULL DEFAULT '';
+ALTER TABLE clients ADD COLUMN city TEXT NOT NULL DEFAULT '';
+
+-- People at the client side: site engineers, residents, purchasing...
+CREATE TABLE client_contacts (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    client_id        INTEGER NOT NULL REFERENCES clients(id),
+    name             TEXT NOT NULL,
+    role             TEXT NOT NULL DEFAULT '',
+    email            TEXT NOT NULL DEFAULT '',
+    phone            TEXT NOT NULL DEFAULT '',
+    receives_reports INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX client_contacts_client_id ON client_contacts (client_id);
+
+-- Recipients chosen for an issued report. Name and email are copied so the
+-- record survives later edits or removal of the contact.
+CREATE TABLE issued_report_recipients (
+    issued_report_id INTEGER NOT NULL REFERENCES issued_reports(id),
+    contact_id       INTEGER REFERENCES client_contacts(id) ON DELETE SET NULL,
+    name             TEXT NOT NULL,
+    email            TEXT NOT NULL
+);
+
+CREATE INDEX issued_report_recipients_report_id ON issued_report_recipients (issued_report_id);
@@ -0,0 +1,14 @@
+-- Site and contract data printed on the report header.
+ALTER TABLE projects ADD COLUMN address TEXT NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN city TEXT NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN latitude REAL;
+ALTER TABLE projects ADD COLUMN longitude REAL;
+ALTER TABLE projects ADD COLUMN contractor TEXT NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN supervising_engineer TEXT NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN contract_number TEXT NOT NULL DEFAULT '';
+-- YYYY-MM-DD text, see domain.Date.
+ALTER TABLE projects ADD COLUMN start_date TEXT;
+ALTER TABLE projects ADD COLUMN end_date TEXT;
+ALTER TABLE projects ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
+
+CREATE INDEX projects_status ON projects (status);
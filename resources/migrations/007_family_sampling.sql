@@ -0,0 +1,9 @@
+-- Field sampling data recorded by the inspector when the family is cast.
+ALTER TABLE families ADD COLUMN slump_cm REAL;
+ALTER TABLE families ADD COLUMN concrete_temperature_c REAL;
+ALTER TABLE families ADD COLUMN ambient_temperature_c REAL;
+ALTER TABLE families ADD COLUMN supplier TEXT NOT NULL DEFAULT '';
+ALTER TABLE families ADD COLUMN truck_ticket TEXT NOT NULL DEFAULT '';
+ALTER TABLE families ADD COLUMN mix_design_code TEXT NOT NULL DEFAULT '';
+ALTER TABLE families ADD COLUMN structural_element TEXT NOT NULL DEFAULT '';
+ALTER TABLE families ADD COLUMN sampling_technician TEXT NOT NULL DEFAULT '';
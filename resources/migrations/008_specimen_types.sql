@@ -0,0 +1,7 @@
+-- Specimen type of the family and the geometry each type needs. Existing
+-- families were all computed as cylinders.
+ALTER TABLE families ADD COLUMN specimen_type TEXT NOT NULL DEFAULT 'cylinder';
+ALTER TABLE families ADD COLUMN side REAL NOT NULL DEFAULT 0;
+ALTER TABLE families ADD COLUMN width REAL NOT NULL DEFAULT 0;
+ALTER TABLE families ADD COLUMN depth REAL NOT NULL DEFAULT 0;
+ALTER TABLE families ADD COLUMN span REAL NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- Dimensions and mass measured on each specimen before the break. They are
+-- optional; the family's nominal geometry is used when missing.
+ALTER TABLE members ADD COLUMN diameter_1_cm REAL;
+ALTER TABLE members ADD COLUMN diameter_2_cm REAL;
+ALTER TABLE members ADD COLUMN height_cm REAL;
+ALTER TABLE members ADD COLUMN mass_kg REAL;
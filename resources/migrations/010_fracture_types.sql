@@ -0,0 +1,10 @@
+-- fracture_type now holds a code of the member.FractureTypes catalogue.
+-- Legacy free-text values naming a type ("Tipo 3", "type 3", "3") are
+-- converted; anything else is kept and shown as is.
+UPDATE members
+SET fracture_type = substr(trim(fracture_type), -1)
+WHERE lower(trim(fracture_type)) IN (
+    '1', '2', '3', '4', '5', '6',
+    'tipo 1', 'tipo 2', 'tipo 3', 'tipo 4', 'tipo 5', 'tipo 6',
+    'type 1', 'type 2', 'type 3', 'type 4', 'type 5', 'type 6'
+);
@@ -0,0 +1,4 @@
+-- End preparation checks done on each specimen before the break.
+ALTER TABLE members ADD COLUMN perpendicular BOOLEAN;
+ALTER TABLE members ADD COLUMN capping_method TEXT;
+ALTER TABLE members ADD COLUMN planeness_ok BOOLEAN;
@@ -0,0 +1,30 @@
+-- Testing machines and load cells with their calibration certificates.
+CREATE TABLE equipment (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind          TEXT NOT NULL,
+    name          TEXT NOT NULL,
+    manufacturer  TEXT NOT NULL DEFAULT '',
+    model         TEXT NOT NULL DEFAULT '',
+    serial_number TEXT NOT NULL DEFAULT '',
+    capacity_kn   REAL,
+    -- Machine a load cell is mounted on.
+    machine_id    INTEGER REFERENCES equipment(id),
+    retired       BOOLEAN NOT NULL DEFAULT 0
+);
+
+CREATE TABLE equipment_calibrations (
+    id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+    equipment_id       INTEGER NOT NULL REFERENCES equipment(id),
+    certificate_number TEXT NOT NULL,
+    laboratory         TEXT NOT NULL DEFAULT '',
+    calibrated_on      TEXT NOT NULL,
+    valid_until        TEXT NOT NULL,
+    notes              TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_equipment_calibrations_equipment ON equipment_calibrations(equipment_id, valid_until);
+
+-- Machine each member was broken on.
+ALTER TABLE members ADD COLUMN machine_id INTEGER REFERENCES equipment(id);
+
+CREATE INDEX idx_members_machine ON members(machine_id);
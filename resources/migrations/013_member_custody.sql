@@ -0,0 +1,16 @@
+-- Chain of custody of each specimen from its arrival at the lab until it is
+-- taken out for the break.
+CREATE TABLE member_custody_events (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    member_id     INTEGER NOT NULL REFERENCES members(id),
+    event         TEXT NOT NULL,
+    location      TEXT NOT NULL DEFAULT '',
+    -- Water or room temperature read when the event was recorded.
+    temperature_c REAL,
+    notes         TEXT NOT NULL DEFAULT '',
+    recorded_by   INTEGER NOT NULL REFERENCES users(id),
+    occurred_at   DATETIME NOT NULL
+);
+
+CREATE INDEX member_custody_events_member_id ON member_custody_events (member_id, id);
+CREATE INDEX member_custody_events_location ON member_custody_events (location);
@@ -0,0 +1,11 @@
+-- Load series captured by the press while fracturing a member. points holds
+-- the samples as zlib-compressed little-endian float32 (x, load) pairs.
+CREATE TABLE member_curves (
+    member_id   INTEGER PRIMARY KEY REFERENCES members(id),
+    axis        TEXT NOT NULL,
+    point_count INTEGER NOT NULL,
+    points      BLOB NOT NULL,
+    peak_load   REAL NOT NULL,
+    recorded_by INTEGER NOT NULL REFERENCES users(id),
+    recorded_at DATETIME NOT NULL
+);
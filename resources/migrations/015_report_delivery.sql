@@ -0,0 +1,10 @@
+-- The PDF handed out, kept so the report can be emailed again later.
+ALTER TABLE issued_reports ADD COLUMN file BLOB;
+
+-- Email delivery of the report to each recipient. status is pending until
+-- the first attempt, then sent or failed; attempts counts every try.
+ALTER TABLE issued_report_recipients ADD COLUMN status TEXT NOT NULL DEFAULT 'pending';
+ALTER TABLE issued_report_recipients ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE issued_report_recipients ADD COLUMN last_error TEXT;
+ALTER TABLE issued_report_recipients ADD COLUMN last_attempt_at DATETIME;
+ALTER TABLE issued_report_recipients ADD COLUMN sent_at DATETIME;
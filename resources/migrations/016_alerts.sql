@@ -0,0 +1,55 @@
+-- Rules checked every time a result is recorded. A rule fires when the
+-- strength of a member falls below threshold_percent of the family's design
+-- strength; the optional columns narrow it to a project, specimen type or
+-- age. emails is a comma separated list of extra addresses to notify.
+CREATE TABLE alert_rules (
+    id                INTEGER PRIMARY KEY AUTOINCREMENT,
+    name              TEXT NOT NULL,
+    threshold_percent REAL NOT NULL,
+    project_id        INTEGER REFERENCES projects(id),
+    specimen_type     TEXT NOT NULL DEFAULT '',
+    age_days          INTEGER,
+    emails            TEXT NOT NULL DEFAULT '',
+    notify_client     BOOLEAN NOT NULL DEFAULT 0,
+    webhook_url       TEXT NOT NULL DEFAULT '',
+    active            BOOLEAN NOT NULL DEFAULT 1,
+    created_by        INTEGER REFERENCES users(id),
+    created_at        DATETIME NOT NULL
+);
+
+-- Alert inbox. Strengths are in PSI, as computed when the alert was raised.
+CREATE TABLE alerts (
+    id                INTEGER PRIMARY KEY AUTOINCREMENT,
+    rule_id           INTEGER NOT NULL REFERENCES alert_rules(id),
+    member_id         INTEGER NOT NULL REFERENCES members(id),
+    family_id         INTEGER NOT NULL REFERENCES families(id),
+    project_id        INTEGER REFERENCES projects(id),
+    result            REAL NOT NULL,
+    strength_psi      REAL NOT NULL,
+    design_resistance REAL NOT NULL,
+    percent           REAL NOT NULL,
+    age_days          INTEGER,
+    status            TEXT NOT NULL DEFAULT 'open',
+    created_at        DATETIME NOT NULL,
+    acknowledged_by   INTEGER REFERENCES users(id),
+    acknowledged_at   DATETIME,
+    resolved_by       INTEGER REFERENCES users(id),
+    resolved_at       DATETIME,
+    resolution_note   TEXT
+);
+
+CREATE INDEX alerts_status ON alerts (status, id);
+CREATE INDEX alerts_member_id ON alerts (member_id);
+
+-- Every email or webhook sent for an alert and its outcome.
+CREATE TABLE alert_notifications (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    alert_id   INTEGER NOT NULL REFERENCES alerts(id),
+    channel    TEXT NOT NULL,
+    target     TEXT NOT NULL,
+    status     TEXT NOT NULL,
+    error      TEXT,
+    sent_at    DATETIME NOT NULL
+);
+
+CREATE INDEX alert_notifications_alert_id ON alert_notifications (alert_id);